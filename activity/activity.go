@@ -22,6 +22,7 @@ package activity
 
 import (
 	"context"
+	"time"
 
 	"github.com/uber-go/tally"
 	"go.uber.org/cadence/internal"
@@ -37,6 +38,9 @@ type (
 
 	// RegisterOptions consists of options for registering an activity
 	RegisterOptions = internal.RegisterActivityOptions
+
+	// Heartbeater throttles the heartbeats of an activity. See NewHeartbeater.
+	Heartbeater = internal.Heartbeater
 )
 
 // ErrResultPending is returned from activity's implementation to indicate the activity is not completed when
@@ -116,6 +120,35 @@ func GetHeartbeatDetails(ctx context.Context, d ...interface{}) error {
 	return internal.GetHeartbeatDetails(ctx, d...)
 }
 
+// WithHeartbeat starts heartbeating the currently executing activity in the background. Every interval the value
+// returned by getProgress is recorded as heartbeat details, so when the activity is scheduled with a retry policy the
+// next attempt can resume from the latest reported progress through HasHeartbeatDetails and GetHeartbeatDetails.
+// If interval is not positive, half of the activity HeartbeatTimeout is used.
+//
+// The returned context is canceled when the workflow requests cancellation of the activity or when the returned stop
+// function is called. Activity code should select on its Done channel and call stop once the work is finished.
+//  ctx, stop := activity.WithHeartbeat(ctx, 5*time.Second, func() interface{} { return processed })
+//  defer stop()
+func WithHeartbeat(ctx context.Context, interval time.Duration, getProgress func() interface{}) (context.Context, func()) {
+	return internal.WithHeartbeat(ctx, interval, getProgress)
+}
+
+// NewHeartbeater creates a Heartbeater for the activity of ctx that sends at most one heartbeat per interval, so that
+// activity code can call Beat as often as it likes, for example on every iteration of its main loop:
+//  heartbeater := activity.NewHeartbeater(ctx, 10*time.Second)
+//  for _, item := range items {
+//    if err := heartbeater.Beat(progress(item)); err != nil {
+//      return err
+//    }
+//    ...
+//  }
+// Beat sends the latest details once the interval elapsed and returns a CanceledError once the server reported that
+// the activity was canceled. Pick an interval shorter than the HeartbeatTimeout of the activity.
+// NewHeartbeater panics if interval is not positive.
+func NewHeartbeater(ctx context.Context, interval time.Duration) *Heartbeater {
+	return internal.NewHeartbeater(ctx, interval)
+}
+
 // GetWorkerStopChannel returns a read-only channel. The closure of this channel indicates the activity worker is stopping.
 // When the worker is stopping, it will close this channel and wait until the worker stop timeout finishes. After the timeout
 // hit, the worker will cancel the activity context and then exit. The timeout can be defined by worker option: WorkerStopTimeout.
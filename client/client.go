@@ -413,6 +413,14 @@ func NewClient(service workflowserviceclient.Interface, domain string, options *
 	return internal.NewClient(service, domain, options)
 }
 
+// CompleteActivity reports the completion of an activity that returned activity.ErrResultPending, identified by its
+// task token, without having to create a Client first. It behaves like Client.CompleteActivity of a client created
+// with default options, the result is encoded with the default data converter. Use a Client created with
+// Options.DataConverter when the activity worker uses a custom data converter.
+func CompleteActivity(ctx context.Context, service workflowserviceclient.Interface, taskToken []byte, result interface{}, err error) error {
+	return internal.CompleteActivity(ctx, service, taskToken, result, err)
+}
+
 // NewDomainClient creates an instance of a domain client, to manage lifecycle of domains.
 func NewDomainClient(service workflowserviceclient.Interface, options *Options) DomainClient {
 	return internal.NewDomainClient(service, options)
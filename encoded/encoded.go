@@ -42,9 +42,36 @@ type (
 	// Cadence support using different DataConverters for different activity/childWorkflow in same workflow.
 	//   2. Activity/Workflow worker that run these activity/childWorkflow, through worker.Options.
 	DataConverter = internal.DataConverter
+
+	// DataConverterOptions is used to configure a data converter created by NewDefaultDataConverter.
+	DataConverterOptions = internal.DataConverterOptions
 )
 
 // GetDefaultDataConverter return default data converter used by Cadence worker
 func GetDefaultDataConverter() DataConverter {
 	return internal.DefaultDataConverter
 }
+
+// NewDefaultDataConverter creates a data converter that behaves like the default data converter configured with the
+// provided options. For example, to fail decoding of payloads that contain fields unknown to the target struct:
+//  dc := encoded.NewDefaultDataConverter(encoded.DataConverterOptions{StrictDecoding: true})
+func NewDefaultDataConverter(options DataConverterOptions) DataConverter {
+	return internal.NewDefaultDataConverter(options)
+}
+
+// NewCompressingDataConverter creates a data converter that gzips the payloads produced by inner and decompresses
+// payloads before handing them to inner. It reduces the size of large payloads like big json blobs in the history.
+// Both sides of the wire must use it: when used for activities, the activity worker must be configured with the same
+// converter through worker.Options. A nil inner means the default data converter.
+func NewCompressingDataConverter(inner DataConverter) DataConverter {
+	return internal.NewCompressingDataConverter(inner)
+}
+
+// RegisterDomainDataConverter registers the DataConverter used for the payloads of domain. Clients and workers created
+// for domain without an explicit DataConverter use it, and so do child workflows started in domain from a workflow of
+// another domain, so that their arguments and results are encoded the way the workers of domain expect.
+// Domains without a registered DataConverter fall back to the default data converter. Registering a domain again
+// replaces its DataConverter; clients and workers that were already created keep the previous one.
+func RegisterDomainDataConverter(domain string, converter DataConverter) {
+	internal.RegisterDomainDataConverter(domain, converter)
+}
@@ -24,6 +24,7 @@ package internal
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -99,6 +100,13 @@ type (
 		// Optional: default false
 		WaitForCancellation bool
 
+		// CancellationGracePeriod - When WaitForCancellation is set, bounds how long the workflow waits for a canceled
+		// activity to complete. Once the grace period elapsed the Future of the activity is resolved with CanceledError,
+		// even if the activity is still running.
+		// ExecuteActivityParameters.CancellationGracePeriodSeconds overrides it when both are set.
+		// Optional: default 0, means the workflow waits for the activity as long as WaitForCancellation requires.
+		CancellationGracePeriod time.Duration
+
 		// ActivityID - Business level activity ID, this is not needed for most of the cases if you have
 		// to specify this then talk to cadence team. This is something will be done in future.
 		// Optional: default empty string
@@ -113,6 +121,13 @@ type (
 		// Same apply to ScheduleToCloseTimeout. See more details about RetryPolicy on the doc for RetryPolicy.
 		// Optional: default is no retry
 		RetryPolicy *RetryPolicy
+
+		// OnComplete - A hook invoked with the encoded result and the error of the activity when it is resolved, right
+		// before the Future returned by ExecuteActivity becomes ready. Useful for cross-cutting concerns like metrics or
+		// tracing. The hook is invoked by the workflow dispatcher in history order, including during replay, so it must
+		// not block nor call any workflow blocking functions.
+		// Optional: default nil
+		OnComplete func(result []byte, err error) `json:"-"`
 	}
 
 	// LocalActivityOptions stores local activity specific parameters that will be stored inside of a context.
@@ -251,7 +266,6 @@ func GetWorkerStopChannel(ctx context.Context) <-chan struct{} {
 // the context with error context.Canceled.
 //  TODO: we don't have a way to distinguish between the two cases when context is cancelled because
 //  context doesn't support overriding value of ctx.Error.
-//  TODO: Implement automatic heartbeating with cancellation through ctx.
 // details - the details that you provided here can be seen in the worflow when it receives TimeoutError, you
 // can check error TimeoutType()/Details().
 func RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
@@ -276,6 +290,71 @@ func RecordActivityHeartbeat(ctx context.Context, details ...interface{}) {
 	}
 }
 
+// WithHeartbeat starts heartbeating the currently executing activity in the background. Every interval the value
+// returned by getProgress is recorded as heartbeat details, so when the activity is scheduled with a retry policy the
+// next attempt can resume from the latest reported progress through HasHeartbeatDetails and GetHeartbeatDetails.
+// If interval is not positive, half of the activity HeartbeatTimeout is used.
+//
+// The returned context is canceled when the workflow requests cancellation of the activity (observed through the
+// heartbeat response) or when the returned stop function is called. Activity code should select on its Done channel
+// and call stop once the work is finished, typically with defer.
+//  ctx, stop := activity.WithHeartbeat(ctx, 5*time.Second, func() interface{} { return processed })
+//  defer stop()
+func WithHeartbeat(ctx context.Context, interval time.Duration, getProgress func() interface{}) (context.Context, func()) {
+	return withHeartbeat(ctx, interval, getProgress, newHeartbeatTicker)
+}
+
+// heartbeatTickerFactory returns a tick channel along with a function to stop it.
+type heartbeatTickerFactory func(d time.Duration) (<-chan time.Time, func())
+
+func newHeartbeatTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
+
+func withHeartbeat(
+	ctx context.Context,
+	interval time.Duration,
+	getProgress func() interface{},
+	newTicker heartbeatTickerFactory,
+) (context.Context, func()) {
+	if interval <= 0 {
+		interval = getActivityEnv(ctx).heartbeatTimeout / 2
+	}
+	heartbeatCtx, cancel := context.WithCancel(ctx)
+	if interval <= 0 {
+		// heartbeat is not required for this activity
+		return heartbeatCtx, cancel
+	}
+
+	ticks, stopTicker := newTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer stopTicker()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticks:
+				var progress interface{}
+				if getProgress != nil {
+					progress = getProgress()
+				}
+				RecordActivityHeartbeat(ctx, progress)
+			}
+		}
+	}()
+
+	var stopOnce sync.Once
+	return heartbeatCtx, func() {
+		stopOnce.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
 // ServiceInvoker abstracts calls to the Cadence service from an activity implementation.
 // Implement to unit test activities.
 type ServiceInvoker interface {
@@ -0,0 +1,144 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/cadence/internal/common"
+)
+
+type (
+	// ActivityOptionsBuilder builds ActivityOptions and validates the relationship between its timeouts
+	// before the options are used to schedule an activity.
+	// The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
+	// subjected to change in the future.
+	ActivityOptionsBuilder interface {
+		WithTaskList(name string) ActivityOptionsBuilder
+		WithScheduleToCloseTimeout(d time.Duration) ActivityOptionsBuilder
+		WithScheduleToStartTimeout(d time.Duration) ActivityOptionsBuilder
+		WithStartToCloseTimeout(d time.Duration) ActivityOptionsBuilder
+		WithHeartbeatTimeout(d time.Duration) ActivityOptionsBuilder
+		WithWaitForCancellation(wait bool) ActivityOptionsBuilder
+		WithCancellationGracePeriod(d time.Duration) ActivityOptionsBuilder
+		WithActivityID(activityID string) ActivityOptionsBuilder
+		WithRetryPolicy(retryPolicy *RetryPolicy) ActivityOptionsBuilder
+		WithOnComplete(hook func(result []byte, err error)) ActivityOptionsBuilder
+		// Build returns the ActivityOptions, or an error if a mandatory timeout is missing or the timeouts
+		// contradict each other.
+		Build() (ActivityOptions, error)
+	}
+
+	activityOptionsBuilderImpl struct {
+		options ActivityOptions
+	}
+)
+
+// NewActivityOptions creates a new ActivityOptionsBuilder
+func NewActivityOptions() ActivityOptionsBuilder {
+	return &activityOptionsBuilderImpl{}
+}
+
+func (b *activityOptionsBuilderImpl) WithTaskList(name string) ActivityOptionsBuilder {
+	b.options.TaskList = name
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithScheduleToCloseTimeout(d time.Duration) ActivityOptionsBuilder {
+	b.options.ScheduleToCloseTimeout = d
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithScheduleToStartTimeout(d time.Duration) ActivityOptionsBuilder {
+	b.options.ScheduleToStartTimeout = d
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithStartToCloseTimeout(d time.Duration) ActivityOptionsBuilder {
+	b.options.StartToCloseTimeout = d
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithHeartbeatTimeout(d time.Duration) ActivityOptionsBuilder {
+	b.options.HeartbeatTimeout = d
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithWaitForCancellation(wait bool) ActivityOptionsBuilder {
+	b.options.WaitForCancellation = wait
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithCancellationGracePeriod(d time.Duration) ActivityOptionsBuilder {
+	b.options.CancellationGracePeriod = d
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithActivityID(activityID string) ActivityOptionsBuilder {
+	b.options.ActivityID = activityID
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithRetryPolicy(retryPolicy *RetryPolicy) ActivityOptionsBuilder {
+	b.options.RetryPolicy = retryPolicy
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) WithOnComplete(hook func(result []byte, err error)) ActivityOptionsBuilder {
+	b.options.OnComplete = hook
+	return b
+}
+
+func (b *activityOptionsBuilderImpl) Build() (ActivityOptions, error) {
+	// Validate against the second based values that are sent to the server, not the raw durations.
+	scheduleToClose := common.Int32Ceil(b.options.ScheduleToCloseTimeout.Seconds())
+	scheduleToStart := common.Int32Ceil(b.options.ScheduleToStartTimeout.Seconds())
+	startToClose := common.Int32Ceil(b.options.StartToCloseTimeout.Seconds())
+	heartbeat := common.Int32Ceil(b.options.HeartbeatTimeout.Seconds())
+
+	if scheduleToStart <= 0 {
+		return ActivityOptions{}, errors.New("missing or negative ScheduleToStartTimeout")
+	}
+	if startToClose <= 0 {
+		return ActivityOptions{}, errors.New("missing or negative StartToCloseTimeout")
+	}
+	if scheduleToClose < 0 {
+		return ActivityOptions{}, errors.New("invalid negative ScheduleToCloseTimeout")
+	}
+	if scheduleToClose > 0 && startToClose > scheduleToClose {
+		return ActivityOptions{}, errors.New("StartToCloseTimeout cannot be larger than ScheduleToCloseTimeout")
+	}
+	if heartbeat < 0 {
+		return ActivityOptions{}, errors.New("invalid negative HeartbeatTimeout")
+	}
+	if b.options.CancellationGracePeriod < 0 {
+		return ActivityOptions{}, errors.New("invalid negative CancellationGracePeriod")
+	}
+	if b.options.RetryPolicy != nil {
+		retryPolicy := *b.options.RetryPolicy
+		if err := validateRetryPolicy(convertRetryPolicy(&retryPolicy)); err != nil {
+			return ActivityOptions{}, err
+		}
+	}
+	return b.options, nil
+}
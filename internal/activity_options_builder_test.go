@@ -0,0 +1,131 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityOptionsBuilder(t *testing.T) {
+	testCases := []struct {
+		msg         string
+		builder     ActivityOptionsBuilder
+		expectedErr string
+	}{
+		{
+			msg: "valid options",
+			builder: NewActivityOptions().
+				WithTaskList("tl").
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(time.Minute).
+				WithScheduleToCloseTimeout(2 * time.Minute).
+				WithHeartbeatTimeout(10 * time.Second).
+				WithWaitForCancellation(true).
+				WithCancellationGracePeriod(30 * time.Second),
+		},
+		{
+			msg: "schedule to close is optional",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(time.Minute),
+		},
+		{
+			msg: "missing schedule to start",
+			builder: NewActivityOptions().
+				WithStartToCloseTimeout(time.Minute),
+			expectedErr: "missing or negative ScheduleToStartTimeout",
+		},
+		{
+			msg: "missing start to close",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute),
+			expectedErr: "missing or negative StartToCloseTimeout",
+		},
+		{
+			msg: "start to close exceeds schedule to close",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(2 * time.Minute).
+				WithScheduleToCloseTimeout(time.Minute),
+			expectedErr: "StartToCloseTimeout cannot be larger than ScheduleToCloseTimeout",
+		},
+		{
+			msg: "sub second difference is rounded up",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(1500 * time.Millisecond).
+				WithScheduleToCloseTimeout(2 * time.Second),
+		},
+		{
+			msg: "negative heartbeat",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(time.Minute).
+				WithHeartbeatTimeout(-time.Second),
+			expectedErr: "invalid negative HeartbeatTimeout",
+		},
+		{
+			msg: "negative cancellation grace period",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(time.Minute).
+				WithWaitForCancellation(true).
+				WithCancellationGracePeriod(-time.Second),
+			expectedErr: "invalid negative CancellationGracePeriod",
+		},
+		{
+			msg: "invalid retry policy",
+			builder: NewActivityOptions().
+				WithScheduleToStartTimeout(time.Minute).
+				WithStartToCloseTimeout(time.Minute).
+				WithRetryPolicy(&RetryPolicy{MaximumAttempts: 3}),
+			expectedErr: "missing or negative InitialIntervalInSeconds on retry policy",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.msg, func(t *testing.T) {
+			options, err := test.builder.Build()
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotZero(t, options.StartToCloseTimeout)
+		})
+	}
+}
+
+func TestActivityOptionsBuilder_CancellationGracePeriod(t *testing.T) {
+	options, err := NewActivityOptions().
+		WithScheduleToStartTimeout(time.Minute).
+		WithStartToCloseTimeout(time.Minute).
+		WithWaitForCancellation(true).
+		WithCancellationGracePeriod(30 * time.Second).
+		Build()
+	require.NoError(t, err)
+	require.True(t, options.WaitForCancellation)
+	require.Equal(t, 30*time.Second, options.CancellationGracePeriod)
+}
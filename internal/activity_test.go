@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
@@ -114,6 +115,54 @@ func (s *activityTestSuite) TestActivityHeartbeat_EntityNotExist() {
 	require.Equal(s.T(), ctx.Err(), context.Canceled)
 }
 
+func (s *activityTestSuite) TestHeartbeater() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	invoker := newServiceInvoker([]byte("task-token"), "identity", s.service, cancel, 10, make(chan struct{}))
+	ctx = context.WithValue(ctx, activityEnvContextKey, &activityEnvironment{
+		serviceInvoker: invoker,
+		logger:         getLogger()})
+
+	sent := make(chan string, 10)
+	s.service.EXPECT().RecordActivityTaskHeartbeat(gomock.Any(), gomock.Any(), callOptions...).
+		Return(&shared.RecordActivityTaskHeartbeatResponse{}, nil).
+		Do(func(ctx context.Context, request *shared.RecordActivityTaskHeartbeatRequest, opts ...yarpc.CallOption) {
+			var details []byte
+			s.NoError(newEncodedValues(request.Details, nil).Get(&details))
+			sent <- string(details)
+		}).Times(2)
+
+	heartbeater := NewHeartbeater(ctx, 100*time.Millisecond)
+	s.NoError(heartbeater.Beat([]byte("1")))
+	s.Equal("1", <-sent)
+	// coalesced into a single heartbeat with the latest details once the interval elapsed
+	s.NoError(heartbeater.Beat([]byte("2")))
+	s.NoError(heartbeater.Beat([]byte("3")))
+	s.Equal("3", <-sent)
+	invoker.Close(false)
+
+	s.Panics(func() { NewHeartbeater(ctx, 0) })
+}
+
+func (s *activityTestSuite) TestHeartbeater_CancelRequested() {
+	ctx, cancel := context.WithCancel(context.Background())
+	invoker := newServiceInvoker([]byte("task-token"), "identity", s.service, cancel, 10, make(chan struct{}))
+	ctx = context.WithValue(ctx, activityEnvContextKey, &activityEnvironment{
+		serviceInvoker: invoker,
+		logger:         getLogger()})
+
+	s.service.EXPECT().RecordActivityTaskHeartbeat(gomock.Any(), gomock.Any(), callOptions...).
+		Return(&shared.RecordActivityTaskHeartbeatResponse{CancelRequested: common.BoolPtr(true)}, nil).Times(1)
+
+	heartbeater := NewHeartbeater(ctx, time.Hour)
+	err := heartbeater.Beat([]byte("1"))
+	s.IsType(&CanceledError{}, err)
+	<-ctx.Done()
+	// no more heartbeats once canceled
+	s.Equal(err, heartbeater.Beat([]byte("2")))
+	invoker.Close(false)
+}
+
 func (s *activityTestSuite) TestActivityHeartbeat_SuppressContinousInvokes() {
 	ctx, cancel := context.WithCancel(context.Background())
 	invoker := newServiceInvoker([]byte("task-token"), "identity", s.service, cancel, 2, make(chan struct{}))
@@ -226,3 +275,63 @@ func (s *activityTestSuite) TestGetWorkerStopChannel() {
 	channel := GetWorkerStopChannel(ctx)
 	s.NotNil(channel)
 }
+
+type recordingServiceInvoker struct {
+	ServiceInvoker
+	heartbeats chan []byte
+}
+
+func (i *recordingServiceInvoker) Heartbeat(details []byte, skipBatching bool) error {
+	i.heartbeats <- details
+	return nil
+}
+
+func (s *activityTestSuite) TestWithHeartbeat() {
+	ctx, cancel := context.WithCancel(context.Background())
+	invoker := &recordingServiceInvoker{heartbeats: make(chan []byte, 1)}
+	ctx = context.WithValue(ctx, activityEnvContextKey, &activityEnvironment{
+		serviceInvoker: invoker,
+		dataConverter:  getDefaultDataConverter(),
+		logger:         getLogger()})
+
+	ticks := make(chan time.Time)
+	tickerStopped := false
+	progress := 0
+	heartbeatCtx, stop := withHeartbeat(ctx, time.Second, func() interface{} {
+		progress++
+		return progress
+	}, func(d time.Duration) (<-chan time.Time, func()) {
+		s.Equal(time.Second, d)
+		return ticks, func() { tickerStopped = true }
+	})
+
+	for expected := 1; expected <= 2; expected++ {
+		ticks <- time.Now()
+		var recorded int
+		s.NoError(decodeArg(getDefaultDataConverter(), <-invoker.heartbeats, &recorded))
+		s.Equal(expected, recorded)
+	}
+
+	// cancellation of the activity context is propagated to the heartbeat context
+	cancel()
+	<-heartbeatCtx.Done()
+	stop()
+	s.True(tickerStopped)
+	s.Equal(context.Canceled, heartbeatCtx.Err())
+}
+
+func (s *activityTestSuite) TestWithHeartbeat_Stop() {
+	ctx := context.WithValue(context.Background(), activityEnvContextKey, &activityEnvironment{
+		heartbeatTimeout: 10 * time.Second,
+	})
+
+	heartbeatCtx, stop := withHeartbeat(ctx, 0, nil, func(d time.Duration) (<-chan time.Time, func()) {
+		s.Equal(5*time.Second, d)
+		return make(chan time.Time), func() {}
+	})
+	s.NoError(heartbeatCtx.Err())
+	stop()
+	stop()
+	s.Equal(context.Canceled, heartbeatCtx.Err())
+	s.NoError(ctx.Err())
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+// AwaitOutcome tells which case completed a wait built with NewAwaitBuilder.
+type AwaitOutcome int
+
+const (
+	// AwaitOutcomeFuture indicates that the future became ready.
+	AwaitOutcomeFuture AwaitOutcome = iota
+	// AwaitOutcomeTimeout indicates that the timeout elapsed before the future became ready.
+	AwaitOutcomeTimeout
+	// AwaitOutcomeCanceled indicates that the context was canceled before the future became ready.
+	AwaitOutcomeCanceled
+)
+
+type (
+	// AwaitBuilder builds a wait for a future that can be bounded by a timeout and interrupted by cancellation.
+	// See NewAwaitBuilder.
+	AwaitBuilder interface {
+		// Future sets the future to wait for. It is mandatory.
+		Future(future Future) AwaitBuilder
+		// OrTimeout stops the wait once d elapsed on the workflow clock. A non positive d means no timeout.
+		OrTimeout(d time.Duration) AwaitBuilder
+		// OrCancel stops the wait when the context is canceled.
+		OrCancel() AwaitBuilder
+		// Do blocks until one of the configured cases happens and returns which one. For AwaitOutcomeFuture, value and
+		// err are the ones the future was set with, encoded results like the ones of ExecuteActivity are returned as
+		// []byte, call Get on the future to decode them. For AwaitOutcomeCanceled, err is the error of the context.
+		Do() (outcome AwaitOutcome, value interface{}, err error)
+	}
+
+	awaitBuilderImpl struct {
+		ctx      Context
+		future   Future
+		timeout  time.Duration
+		orCancel bool
+	}
+)
+
+var errAwaitFutureNotSet = errors.New("await builder requires a future")
+
+// NewAwaitBuilder creates a builder for the common wait for a future, a timeout or a cancellation, which otherwise
+// requires building a Selector by hand:
+//  outcome, _, err := workflow.NewAwaitBuilder(ctx).
+//    Future(activityFuture).
+//    OrTimeout(time.Hour).
+//    OrCancel().
+//    Do()
+// When several cases are ready at the same time, the future wins over the timeout, and the timeout over cancellation.
+// Canceling the context cancels a pending timeout timer as well, which is reported as AwaitOutcomeCanceled.
+func NewAwaitBuilder(ctx Context) AwaitBuilder {
+	return &awaitBuilderImpl{ctx: ctx}
+}
+
+func (b *awaitBuilderImpl) Future(future Future) AwaitBuilder {
+	b.future = future
+	return b
+}
+
+func (b *awaitBuilderImpl) OrTimeout(d time.Duration) AwaitBuilder {
+	b.timeout = d
+	return b
+}
+
+func (b *awaitBuilderImpl) OrCancel() AwaitBuilder {
+	b.orCancel = true
+	return b
+}
+
+func (b *awaitBuilderImpl) Do() (outcome AwaitOutcome, value interface{}, err error) {
+	if b.future == nil {
+		return AwaitOutcomeFuture, nil, errAwaitFutureNotSet
+	}
+	ctx := b.ctx
+	selector := NewSelector(ctx)
+	selector.AddFuture(b.future, func(f Future) {
+		outcome = AwaitOutcomeFuture
+		value, err = f.(asyncFuture).GetValueAndError()
+	})
+	if b.timeout > 0 {
+		timerCtx, cancelTimer := WithCancel(ctx)
+		defer cancelTimer()
+		selector.AddFuture(NewTimer(timerCtx, b.timeout), func(f Future) {
+			if timerErr := f.Get(timerCtx, nil); timerErr != nil {
+				outcome, err = AwaitOutcomeCanceled, ctx.Err()
+				return
+			}
+			outcome = AwaitOutcomeTimeout
+		})
+	}
+	if cancelCh := ctx.Done(); b.orCancel && cancelCh != nil {
+		selector.AddReceive(cancelCh, func(c Channel, more bool) {
+			outcome, err = AwaitOutcomeCanceled, ctx.Err()
+		})
+	}
+	selector.Select(ctx)
+	return outcome, value, err
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+func testAwaitBuilderWorkflow(ctx Context, futureDelay, cancelDelay time.Duration) (string, error) {
+	future, settable := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, futureDelay)
+		settable.SetValue("value")
+	})
+	ctx, cancel := WithCancel(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, cancelDelay)
+		cancel()
+	})
+	outcome, value, err := NewAwaitBuilder(ctx).
+		Future(future).
+		OrTimeout(time.Hour).
+		OrCancel().
+		Do()
+	return fmt.Sprintf("%v-%v-%v", outcome, value, err), nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_AwaitBuilder() {
+	testCases := []struct {
+		name        string
+		futureDelay time.Duration
+		cancelDelay time.Duration
+		expected    string
+	}{
+		{"future", time.Minute, 2 * time.Hour, fmt.Sprintf("%v-value-<nil>", AwaitOutcomeFuture)},
+		{"timeout", 2 * time.Hour, 3 * time.Hour, fmt.Sprintf("%v-<nil>-<nil>", AwaitOutcomeTimeout)},
+		{"cancel", 2 * time.Hour, time.Minute, fmt.Sprintf("%v-<nil>-%v", AwaitOutcomeCanceled, ErrCanceled)},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			env := s.NewTestWorkflowEnvironment()
+			env.RegisterWorkflow(testAwaitBuilderWorkflow)
+			env.ExecuteWorkflow(testAwaitBuilderWorkflow, tc.futureDelay, tc.cancelDelay)
+			s.True(env.IsWorkflowCompleted())
+			s.NoError(env.GetWorkflowError())
+			var result string
+			s.NoError(env.GetWorkflowResult(&result))
+			s.Equal(tc.expected, result)
+		})
+	}
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_AwaitBuilderFutureNotSet() {
+	workflowFn := func(ctx Context) error {
+		_, _, err := NewAwaitBuilder(ctx).OrTimeout(time.Minute).Do()
+		return err
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.EqualError(env.GetWorkflowError(), errAwaitFutureNotSet.Error())
+}
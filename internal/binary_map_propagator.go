@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import "context"
+
+type (
+	// BinaryMapKey is the key under which the propagator returned by NewBinaryMapPropagator stores the []byte value of
+	// a propagated key, in both Go contexts and workflow contexts.
+	BinaryMapKey string
+
+	// binaryMapPropagator propagates raw []byte values of a fixed set of keys across workflows and activities.
+	binaryMapPropagator struct {
+		keys   []string
+		keySet map[string]struct{}
+	}
+)
+
+// NewBinaryMapPropagator returns a ContextPropagator that propagates the []byte values stored under BinaryMapKey(key)
+// for each of keys. Values are copied into the headers as is, so they can hold any binary payload, like a serialized
+// span context. Keys without a value in the context are not propagated.
+//
+//	ctx = context.WithValue(ctx, workflow.BinaryMapKey("span"), spanBytes)
+//	...
+//	spanBytes, _ := ctx.Value(workflow.BinaryMapKey("span")).([]byte)
+func NewBinaryMapPropagator(keys []string) ContextPropagator {
+	keySet := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keySet[key] = struct{}{}
+	}
+	return &binaryMapPropagator{keys: keys, keySet: keySet}
+}
+
+// Inject injects the values of the keys from context into headers.
+func (b *binaryMapPropagator) Inject(ctx context.Context, writer HeaderWriter) error {
+	b.inject(ctx.Value, writer)
+	return nil
+}
+
+// InjectFromWorkflow injects the values of the keys from workflow context into headers.
+func (b *binaryMapPropagator) InjectFromWorkflow(ctx Context, writer HeaderWriter) error {
+	b.inject(ctx.Value, writer)
+	return nil
+}
+
+// Extract extracts the values of the keys from headers and puts them into context.
+func (b *binaryMapPropagator) Extract(ctx context.Context, reader HeaderReader) (context.Context, error) {
+	if err := b.extract(reader, func(key BinaryMapKey, value []byte) {
+		ctx = context.WithValue(ctx, key, value)
+	}); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// ExtractToWorkflow extracts the values of the keys from headers and puts them into workflow context.
+func (b *binaryMapPropagator) ExtractToWorkflow(ctx Context, reader HeaderReader) (Context, error) {
+	if err := b.extract(reader, func(key BinaryMapKey, value []byte) {
+		ctx = WithValue(ctx, key, value)
+	}); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+func (b *binaryMapPropagator) inject(valueOf func(key interface{}) interface{}, writer HeaderWriter) {
+	for _, key := range b.keys {
+		if value, ok := valueOf(BinaryMapKey(key)).([]byte); ok {
+			writer.Set(key, value)
+		}
+	}
+}
+
+func (b *binaryMapPropagator) extract(reader HeaderReader, set func(key BinaryMapKey, value []byte)) error {
+	return reader.ForEachKey(func(key string, value []byte) error {
+		if _, ok := b.keySet[key]; ok {
+			set(BinaryMapKey(key), append([]byte(nil), value...))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+func TestBinaryMapPropagator(t *testing.T) {
+	t.Parallel()
+	span := []byte{0x00, 0xff, 0xfe, 'a', 0x80}
+	auth := []byte("token")
+	propagator := NewBinaryMapPropagator([]string{"span", "auth"})
+
+	// Go context -> header -> workflow context
+	ctx := context.WithValue(context.Background(), BinaryMapKey("span"), span)
+	ctx = context.WithValue(ctx, BinaryMapKey("other"), []byte("not propagated"))
+	header := &shared.Header{Fields: map[string][]byte{}}
+	require.NoError(t, propagator.Inject(ctx, NewHeaderWriter(header)))
+	require.Equal(t, map[string][]byte{"span": span}, header.Fields, "keys without value are skipped")
+
+	header.Fields["unknown"] = []byte("ignored")
+	wfCtx, err := propagator.ExtractToWorkflow(Background(), NewHeaderReader(header))
+	require.NoError(t, err)
+	require.Equal(t, span, wfCtx.Value(BinaryMapKey("span")))
+	require.Nil(t, wfCtx.Value(BinaryMapKey("unknown")))
+
+	// workflow context -> header -> Go context
+	wfCtx = WithValue(wfCtx, BinaryMapKey("auth"), auth)
+	header = &shared.Header{Fields: map[string][]byte{}}
+	require.NoError(t, propagator.InjectFromWorkflow(wfCtx, NewHeaderWriter(header)))
+	require.Equal(t, map[string][]byte{"span": span, "auth": auth}, header.Fields)
+
+	ctx, err = propagator.Extract(context.Background(), NewHeaderReader(header))
+	require.NoError(t, err)
+	require.Equal(t, span, ctx.Value(BinaryMapKey("span")))
+	require.Equal(t, auth, ctx.Value(BinaryMapKey("auth")))
+}
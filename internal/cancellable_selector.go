@@ -0,0 +1,122 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// CancellableSelector is a Selector that also returns from Select when its context is canceled.
+	// See NewCancellableSelector.
+	CancellableSelector interface {
+		Selector
+
+		// WasCanceled returns true if the last call to Select returned because the context was canceled, in which
+		// case none of the added case functions was called.
+		WasCanceled() bool
+	}
+
+	cancellableSelectorImpl struct {
+		*selectorImpl
+		ctx      Context
+		canceled bool
+	}
+)
+
+// NewCancellableSelector creates a Selector whose Select returns as soon as ctx is canceled, even if none of the added
+// cases can ever become ready. It prevents a coroutine from being stuck forever in Select on workflow cancellation:
+//  selector := workflow.NewCancellableSelector(ctx)
+//  selector.AddReceive(requests, handleRequest)
+//  selector.Select(ctx)
+//  if selector.WasCanceled() {
+//    return ctx.Err()
+//  }
+// The cancellation is checked after all the added cases, so a case that is ready at the same time wins. The default
+// case, if any, is only used when ctx is not canceled.
+func NewCancellableSelector(ctx Context) CancellableSelector {
+	return &cancellableSelectorImpl{selectorImpl: NewSelector(ctx).(*selectorImpl), ctx: ctx}
+}
+
+func (s *cancellableSelectorImpl) AddReceive(c Channel, f func(c Channel, more bool)) Selector {
+	s.selectorImpl.AddReceive(c, f)
+	return s
+}
+
+func (s *cancellableSelectorImpl) AddSend(c Channel, v interface{}, f func()) Selector {
+	s.selectorImpl.AddSend(c, v, f)
+	return s
+}
+
+func (s *cancellableSelectorImpl) AddFuture(future Future, f func(f Future)) Selector {
+	s.selectorImpl.AddFuture(future, f)
+	return s
+}
+
+func (s *cancellableSelectorImpl) AddDefault(f func()) Selector {
+	s.selectorImpl.AddDefault(f)
+	return s
+}
+
+func (s *cancellableSelectorImpl) AddWake(f func()) Selector {
+	s.selectorImpl.AddWake(f)
+	return s
+}
+
+func (s *cancellableSelectorImpl) RemoveReceive(c Channel) Selector {
+	s.selectorImpl.RemoveReceive(c)
+	return s
+}
+
+func (s *cancellableSelectorImpl) RemoveFuture(future Future) Selector {
+	s.selectorImpl.RemoveFuture(future)
+	return s
+}
+
+func (s *cancellableSelectorImpl) Select(ctx Context) {
+	s.SelectIndex(ctx)
+}
+
+// SelectIndex returns -1 when the context was canceled, like for the default case. Use WasCanceled to tell them apart.
+func (s *cancellableSelectorImpl) SelectIndex(ctx Context) int {
+	s.canceled = false
+	done := s.ctx.Done()
+	if done == nil {
+		return s.selectorImpl.SelectIndex(ctx)
+	}
+	onCancel := func(c Channel, more bool) {
+		s.canceled = true
+	}
+	cancelCase := &selectCase{channel: done.(*channelImpl), receiveFunc: &onCancel}
+	s.cases = append(s.cases, cancelCase)
+	defer s.removeCases(func(pair *selectCase) bool {
+		return pair == cancelCase
+	})
+	if index := s.selectorImpl.SelectIndex(ctx); !s.canceled {
+		return index
+	}
+	return -1
+}
+
+func (s *cancellableSelectorImpl) SelectWithDefault(ctx Context, f func()) {
+	s.selectWithDefault(f, func() { s.Select(ctx) })
+}
+
+func (s *cancellableSelectorImpl) WasCanceled() bool {
+	return s.canceled
+}
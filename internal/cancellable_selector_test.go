@@ -0,0 +1,97 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testCancellableSelectorWorkflow(ctx Context) ([]string, error) {
+	var events []string
+	requests := NewBufferedChannel(ctx, 1)
+	cancelCtx, cancel := WithCancel(ctx)
+	selector := NewCancellableSelector(cancelCtx)
+	selector.AddReceive(requests, func(c Channel, more bool) {
+		var request string
+		c.Receive(ctx, &request)
+		events = append(events, request)
+	})
+
+	requests.SendAsync("first")
+	selector.Select(ctx)
+	events = append(events, fmt.Sprintf("after-first-canceled-%v", selector.WasCanceled()))
+
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		cancel()
+	})
+	// blocks until the cancellation as no request is sent
+	selector.Select(ctx)
+	if selector.WasCanceled() {
+		events = append(events, "canceled")
+	}
+
+	// a ready case wins over the cancellation
+	requests.SendAsync("second")
+	selector.Select(ctx)
+	events = append(events, fmt.Sprintf("after-second-canceled-%v", selector.WasCanceled()))
+	selector.Select(ctx)
+	events = append(events, fmt.Sprintf("after-third-canceled-%v", selector.WasCanceled()))
+
+	requests.SendAsync("third")
+	events = append(events, fmt.Sprintf("index-%v", selector.SelectIndex(ctx)))
+	events = append(events, fmt.Sprintf("canceled-index-%v", selector.SelectIndex(ctx)))
+	return events, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_CancellableSelector() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testCancellableSelectorWorkflow)
+	env.ExecuteWorkflow(testCancellableSelectorWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var events []string
+	s.NoError(env.GetWorkflowResult(&events))
+	s.Equal([]string{
+		"first", "after-first-canceled-false", "canceled", "second", "after-second-canceled-false", "after-third-canceled-true",
+		"third", "index-0", "canceled-index--1",
+	}, events)
+}
+
+func TestCancellableSelector_NotCancellable(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		// the background context can not be canceled, the selector behaves like a regular one
+		selector := NewCancellableSelector(ctx)
+		called := false
+		selector.AddDefault(func() { called = true })
+		selector.Select(ctx)
+		require.True(t, called)
+		require.False(t, selector.WasCanceled())
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
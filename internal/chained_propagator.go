@@ -0,0 +1,78 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import "context"
+
+// chainedPropagator runs a list of ContextPropagators one after the other.
+type chainedPropagator struct {
+	propagators []ContextPropagator
+}
+
+// NewChainedPropagator returns a ContextPropagator that calls each of propagators in order, so that several of them
+// can be registered as one. Inject and InjectFromWorkflow stop at the first error. Extract and ExtractToWorkflow pass
+// the context returned by a propagator to the next one, and stop at the first error as well.
+func NewChainedPropagator(propagators ...ContextPropagator) ContextPropagator {
+	return &chainedPropagator{propagators: propagators}
+}
+
+// Inject calls Inject of each propagator in order.
+func (c *chainedPropagator) Inject(ctx context.Context, writer HeaderWriter) error {
+	for _, p := range c.propagators {
+		if err := p.Inject(ctx, writer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectFromWorkflow calls InjectFromWorkflow of each propagator in order.
+func (c *chainedPropagator) InjectFromWorkflow(ctx Context, writer HeaderWriter) error {
+	for _, p := range c.propagators {
+		if err := p.InjectFromWorkflow(ctx, writer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Extract calls Extract of each propagator in order, with the context returned by the previous one.
+func (c *chainedPropagator) Extract(ctx context.Context, reader HeaderReader) (context.Context, error) {
+	for _, p := range c.propagators {
+		var err error
+		if ctx, err = p.Extract(ctx, reader); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// ExtractToWorkflow calls ExtractToWorkflow of each propagator in order, with the context returned by the previous one.
+func (c *chainedPropagator) ExtractToWorkflow(ctx Context, reader HeaderReader) (Context, error) {
+	for _, p := range c.propagators {
+		var err error
+		if ctx, err = p.ExtractToWorkflow(ctx, reader); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/.gen/go/shared"
+)
+
+type (
+	chainKey       struct{}
+	chainHeaderKey struct{}
+)
+
+// chainTestPropagator appends its name to the header value and to the context value of chainKey, or fails with err.
+// On extraction it also stores the header value it received under chainHeaderKey.
+type chainTestPropagator struct {
+	name string
+	err  error
+}
+
+func (p *chainTestPropagator) Inject(ctx context.Context, writer HeaderWriter) error {
+	return p.inject(writer)
+}
+
+func (p *chainTestPropagator) InjectFromWorkflow(ctx Context, writer HeaderWriter) error {
+	return p.inject(writer)
+}
+
+func (p *chainTestPropagator) Extract(ctx context.Context, reader HeaderReader) (context.Context, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	prev, _ := ctx.Value(chainKey{}).(string)
+	ctx = context.WithValue(ctx, chainHeaderKey{}, p.headerValue(reader))
+	return context.WithValue(ctx, chainKey{}, prev+p.name), nil
+}
+
+func (p *chainTestPropagator) ExtractToWorkflow(ctx Context, reader HeaderReader) (Context, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	prev, _ := ctx.Value(chainKey{}).(string)
+	ctx = WithValue(ctx, chainHeaderKey{}, p.headerValue(reader))
+	return WithValue(ctx, chainKey{}, prev+p.name), nil
+}
+
+func (p *chainTestPropagator) inject(writer HeaderWriter) error {
+	if p.err != nil {
+		return p.err
+	}
+	w := writer.(*headerWriter)
+	writer.Set("chain", append(w.header.Fields["chain"], p.name...))
+	return nil
+}
+
+func (p *chainTestPropagator) headerValue(reader HeaderReader) string {
+	var value string
+	_ = reader.ForEachKey(func(key string, v []byte) error {
+		if key == "chain" {
+			value = string(v)
+		}
+		return nil
+	})
+	return value
+}
+
+func TestChainedPropagator(t *testing.T) {
+	t.Parallel()
+	chained := NewChainedPropagator(&chainTestPropagator{name: "a"}, &chainTestPropagator{name: "b"})
+
+	header := &shared.Header{Fields: map[string][]byte{}}
+	require.NoError(t, chained.Inject(context.Background(), NewHeaderWriter(header)))
+	require.Equal(t, "ab", string(header.Fields["chain"]))
+	header = &shared.Header{Fields: map[string][]byte{}}
+	require.NoError(t, chained.InjectFromWorkflow(Background(), NewHeaderWriter(header)))
+	require.Equal(t, "ab", string(header.Fields["chain"]))
+
+	ctx, err := chained.Extract(context.Background(), NewHeaderReader(header))
+	require.NoError(t, err)
+	require.Equal(t, "ab", ctx.Value(chainKey{}))
+	wfCtx, err := chained.ExtractToWorkflow(Background(), NewHeaderReader(header))
+	require.NoError(t, err)
+	require.Equal(t, "ab", wfCtx.Value(chainKey{}))
+}
+
+func TestChainedPropagator_Error(t *testing.T) {
+	t.Parallel()
+	failure := errors.New("failure")
+	chained := NewChainedPropagator(
+		&chainTestPropagator{name: "a"},
+		&chainTestPropagator{name: "b", err: failure},
+		&chainTestPropagator{name: "c"},
+	)
+
+	header := &shared.Header{Fields: map[string][]byte{}}
+	require.Equal(t, failure, chained.Inject(context.Background(), NewHeaderWriter(header)))
+	require.Equal(t, "a", string(header.Fields["chain"]), "propagators after the failing one must not run")
+	header = &shared.Header{Fields: map[string][]byte{}}
+	require.Equal(t, failure, chained.InjectFromWorkflow(Background(), NewHeaderWriter(header)))
+	require.Equal(t, "a", string(header.Fields["chain"]))
+
+	_, err := chained.Extract(context.Background(), NewHeaderReader(header))
+	require.Equal(t, failure, err)
+	_, err = chained.ExtractToWorkflow(Background(), NewHeaderReader(header))
+	require.Equal(t, failure, err)
+}
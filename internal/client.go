@@ -510,6 +510,8 @@ func NewClient(service workflowserviceclient.Interface, domain string, options *
 	var dataConverter DataConverter
 	if options != nil && options.DataConverter != nil {
 		dataConverter = options.DataConverter
+	} else if dc := getDomainDataConverter(domain); dc != nil {
+		dataConverter = dc
 	} else {
 		dataConverter = getDefaultDataConverter()
 	}
@@ -536,6 +538,14 @@ func NewClient(service workflowserviceclient.Interface, domain string, options *
 	}
 }
 
+// CompleteActivity reports the completion of an activity that returned ErrActivityResultPending, identified by its
+// task token, without having to create a Client first. It behaves like Client.CompleteActivity of a client created
+// with default options, the result is encoded with the default data converter. Use a Client created with
+// ClientOptions.DataConverter when the activity worker uses a custom data converter.
+func CompleteActivity(ctx context.Context, service workflowserviceclient.Interface, taskToken []byte, result interface{}, err error) error {
+	return NewClient(service, "", nil).CompleteActivity(ctx, taskToken, result, err)
+}
+
 // NewDomainClient creates an instance of a domain client, to manager lifecycle of domains.
 func NewDomainClient(service workflowserviceclient.Interface, options *ClientOptions) DomainClient {
 	var identity string
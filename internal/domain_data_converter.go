@@ -0,0 +1,61 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import "sync"
+
+var domainDataConverters = struct {
+	sync.RWMutex
+	converters map[string]DataConverter
+}{converters: make(map[string]DataConverter)}
+
+// RegisterDomainDataConverter registers the DataConverter used for the payloads of domain. Clients and workers created
+// for domain without an explicit DataConverter use it, and so do child workflows started in domain from a workflow of
+// another domain, so that their arguments and results are encoded the way the workers of domain expect.
+// Domains without a registered DataConverter fall back to the default data converter. Registering a domain again
+// replaces its DataConverter; clients and workers that were already created keep the previous one.
+func RegisterDomainDataConverter(domain string, converter DataConverter) {
+	if domain == "" {
+		panic("domain is empty for RegisterDomainDataConverter")
+	}
+	if converter == nil {
+		panic("data converter is nil for RegisterDomainDataConverter")
+	}
+	domainDataConverters.Lock()
+	defer domainDataConverters.Unlock()
+	domainDataConverters.converters[domain] = converter
+}
+
+// getDomainDataConverter returns the DataConverter registered for domain, or nil when there is none.
+func getDomainDataConverter(domain string) DataConverter {
+	domainDataConverters.RLock()
+	defer domainDataConverters.RUnlock()
+	return domainDataConverters.converters[domain]
+}
+
+// unregisterDomainDataConverter removes the DataConverter registered for domain, so that tests don't leak the converters
+// they registered into other tests.
+func unregisterDomainDataConverter(domain string) {
+	domainDataConverters.Lock()
+	defer domainDataConverters.Unlock()
+	delete(domainDataConverters.converters, domain)
+}
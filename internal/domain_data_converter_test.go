@@ -0,0 +1,112 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingDataConverter records how many payloads it encoded and decoded.
+type countingDataConverter struct {
+	DataConverter
+	toData, fromData int
+}
+
+func (dc *countingDataConverter) ToData(value ...interface{}) ([]byte, error) {
+	dc.toData++
+	return dc.DataConverter.ToData(value...)
+}
+
+func (dc *countingDataConverter) FromData(input []byte, valuePtr ...interface{}) error {
+	dc.fromData++
+	return dc.DataConverter.FromData(input, valuePtr...)
+}
+
+func testDomainDataConverterChildWorkflow(ctx Context, name string) (string, error) {
+	return name + "@" + GetWorkflowInfo(ctx).Domain, nil
+}
+
+func testDomainDataConverterWorkflow(ctx Context) ([]string, error) {
+	ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{
+		ExecutionStartToCloseTimeout: time.Minute,
+		TaskStartToCloseTimeout:      time.Minute,
+	})
+	var result []string
+	for _, domain := range []string{"dc-test-domain-gob", "dc-test-domain-gzip", "dc-test-domain-unregistered"} {
+		var r string
+		if err := ExecuteChildWorkflow(WithWorkflowDomain(ctx, domain), testDomainDataConverterChildWorkflow, "child").Get(ctx, &r); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_RegisterDomainDataConverterChildWorkflow() {
+	gobDC := &countingDataConverter{DataConverter: newTestDataConverter()}
+	gzipDC := &countingDataConverter{DataConverter: NewCompressingDataConverter(nil)}
+	RegisterDomainDataConverter("dc-test-domain-gob", gobDC)
+	defer unregisterDomainDataConverter("dc-test-domain-gob")
+	RegisterDomainDataConverter("dc-test-domain-gzip", gzipDC)
+	defer unregisterDomainDataConverter("dc-test-domain-gzip")
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testDomainDataConverterWorkflow)
+	env.RegisterWorkflow(testDomainDataConverterChildWorkflow)
+	env.ExecuteWorkflow(testDomainDataConverterWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{
+		"child@dc-test-domain-gob",
+		"child@dc-test-domain-gzip",
+		"child@dc-test-domain-unregistered",
+	}, result)
+
+	// payloads of a domain can only be decoded with the converter of that domain, so the results above already
+	// show that the converters were not mixed up, make sure that the registered ones were used at all
+	s.Equal(2, gobDC.toData, "input and result of the child")
+	s.NotZero(gobDC.fromData)
+	s.Equal(2, gzipDC.toData, "input and result of the child")
+	s.NotZero(gzipDC.fromData)
+}
+
+func TestRegisterDomainDataConverter_Client(t *testing.T) {
+	dc := newTestDataConverter()
+	RegisterDomainDataConverter("dc-test-domain-client", dc)
+	defer unregisterDomainDataConverter("dc-test-domain-client")
+
+	require.Equal(t, dc, NewClient(nil, "dc-test-domain-client", nil).(*workflowClient).dataConverter)
+	require.Equal(t, getDefaultDataConverter(), NewClient(nil, "dc-test-domain-other", nil).(*workflowClient).dataConverter)
+	explicit := NewCompressingDataConverter(nil)
+	require.Equal(t, explicit, NewClient(nil, "dc-test-domain-client", &ClientOptions{DataConverter: explicit}).(*workflowClient).dataConverter)
+
+	require.Panics(t, func() { RegisterDomainDataConverter("", dc) })
+	require.Panics(t, func() { RegisterDomainDataConverter("dc-test-domain-client", nil) })
+
+	unregisterDomainDataConverter("dc-test-domain-client")
+	require.Equal(t, getDefaultDataConverter(), NewClient(nil, "dc-test-domain-client", nil).(*workflowClient).dataConverter)
+}
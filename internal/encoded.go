@@ -21,6 +21,9 @@
 package internal
 
 import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
 	"reflect"
 
 	"go.uber.org/cadence/internal/common"
@@ -62,15 +65,41 @@ type (
 		FromData(input []byte, valuePtr ...interface{}) error
 	}
 
+	// DataConverterOptions is used to configure a data converter created by NewDefaultDataConverter.
+	DataConverterOptions struct {
+		// StrictDecoding makes decoding of json encoded values fail when the payload contains a field that does not
+		// exist on the target struct. This is useful to catch schema drift between the producer and the consumer of a
+		// value. Fields that are missing from the payload are still decoded as zero values.
+		// Optional: default false, unknown fields are ignored.
+		StrictDecoding bool
+	}
+
 	// defaultDataConverter uses thrift encoder/decoder when possible, for everything else use json.
-	defaultDataConverter struct{}
+	defaultDataConverter struct {
+		strictDecoding bool
+	}
+
+	// compressingDataConverter gzips the payloads produced by the wrapped data converter.
+	compressingDataConverter struct {
+		inner DataConverter
+	}
 )
 
 var defaultJSONDataConverter = &defaultDataConverter{}
 
-// DefaultDataConverter is default data converter used by Cadence worker
+// DefaultDataConverter is default data converter used by Cadence worker.
+// Values that are not thrift types are encoded as json. Decoding is tolerant to schema evolution of structs:
+// fields in the payload that do not exist on the target struct are ignored, and fields of the target struct that are
+// missing from the payload are left with their zero values. Use NewDefaultDataConverter with
+// DataConverterOptions.StrictDecoding to reject unknown fields instead.
 var DefaultDataConverter = getDefaultDataConverter()
 
+// NewDefaultDataConverter creates a data converter that behaves like DefaultDataConverter configured with the
+// provided options.
+func NewDefaultDataConverter(options DataConverterOptions) DataConverter {
+	return &defaultDataConverter{strictDecoding: options.StrictDecoding}
+}
+
 // getDefaultDataConverter return default data converter used by Cadence worker
 func getDefaultDataConverter() DataConverter {
 	return defaultJSONDataConverter
@@ -105,8 +134,51 @@ func (dc *defaultDataConverter) FromData(data []byte, to ...interface{}) error {
 	if common.IsUseThriftDecoding(to) {
 		encoder = &thriftEncoding{}
 	} else {
-		encoder = &jsonEncoding{}
+		encoder = &jsonEncoding{disallowUnknownFields: dc.strictDecoding}
 	}
 
 	return encoder.Unmarshal(data, to)
 }
+
+// NewCompressingDataConverter creates a data converter that gzips the payloads produced by inner and decompresses
+// payloads before handing them to inner. It reduces the size of large payloads like big json blobs in the history.
+// Both sides of the wire must use it: when used for activities, the activity worker must be configured with the same
+// converter through worker.Options. A nil inner means the default data converter.
+func NewCompressingDataConverter(inner DataConverter) DataConverter {
+	if inner == nil {
+		inner = getDefaultDataConverter()
+	}
+	return &compressingDataConverter{inner: inner}
+}
+
+func (dc *compressingDataConverter) ToData(value ...interface{}) ([]byte, error) {
+	data, err := dc.inner.ToData(value...)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (dc *compressingDataConverter) FromData(input []byte, valuePtr ...interface{}) error {
+	if len(input) == 0 {
+		return dc.inner.FromData(input, valuePtr...)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(input))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return dc.inner.FromData(data, valuePtr...)
+}
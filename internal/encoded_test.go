@@ -127,3 +127,71 @@ func TestDecodeArg(t *testing.T) {
 	require.NoError(t, err)
 	require.Error(t, decodeArg(dc, b, &r))
 }
+
+func TestDefaultDataConverter_SchemaEvolution(t *testing.T) {
+	t.Parallel()
+	type resultV1 struct {
+		Name  string
+		Count int
+	}
+	type resultV2 struct {
+		Name  string
+		Count int
+		Owner string
+	}
+
+	newPayload, err := getDefaultDataConverter().ToData(resultV2{Name: "n", Count: 2, Owner: "o"})
+	require.NoError(t, err)
+	oldPayload, err := getDefaultDataConverter().ToData(resultV1{Name: "n", Count: 2})
+	require.NoError(t, err)
+
+	t.Run("lenient extra field", func(t *testing.T) {
+		var r resultV1
+		require.NoError(t, getDefaultDataConverter().FromData(newPayload, &r))
+		require.Equal(t, resultV1{Name: "n", Count: 2}, r)
+	})
+	t.Run("lenient missing field", func(t *testing.T) {
+		var r resultV2
+		require.NoError(t, getDefaultDataConverter().FromData(oldPayload, &r))
+		require.Equal(t, resultV2{Name: "n", Count: 2}, r)
+	})
+
+	strict := NewDefaultDataConverter(DataConverterOptions{StrictDecoding: true})
+	t.Run("strict extra field", func(t *testing.T) {
+		var r resultV1
+		err := strict.FromData(newPayload, &r)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown field")
+	})
+	t.Run("strict missing field", func(t *testing.T) {
+		var r resultV2
+		require.NoError(t, strict.FromData(oldPayload, &r))
+		require.Equal(t, resultV2{Name: "n", Count: 2}, r)
+	})
+}
+
+func TestCompressingDataConverter(t *testing.T) {
+	t.Parallel()
+	type payload struct {
+		Items []string
+	}
+	input := payload{}
+	for i := 0; i < 1000; i++ {
+		input.Items = append(input.Items, "a repetitive value that compresses well")
+	}
+
+	plain, err := getDefaultDataConverter().ToData(input, "second")
+	require.NoError(t, err)
+	dc := NewCompressingDataConverter(nil)
+	compressed, err := dc.ToData(input, "second")
+	require.NoError(t, err)
+	require.True(t, len(compressed) < len(plain), "compressed %v, plain %v", len(compressed), len(plain))
+
+	var output payload
+	var second string
+	require.NoError(t, dc.FromData(compressed, &output, &second))
+	require.Equal(t, input, output)
+	require.Equal(t, "second", second)
+
+	require.Error(t, dc.FromData(plain, &output, &second))
+}
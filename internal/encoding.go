@@ -39,6 +39,7 @@ type encoding interface {
 
 // jsonEncoding encapsulates json encoding and decoding
 type jsonEncoding struct {
+	disallowUnknownFields bool // fail decoding when the payload has fields unknown to the target struct
 }
 
 // Marshal encodes an array of object into bytes
@@ -61,6 +62,9 @@ func (g jsonEncoding) Marshal(objs []interface{}) ([]byte, error) {
 func (g jsonEncoding) Unmarshal(data []byte, objs []interface{}) error {
 	dec := json.NewDecoder(bytes.NewBuffer(data))
 	dec.UseNumber()
+	if g.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
 	for i, obj := range objs {
 		if err := dec.Decode(obj); err != nil {
 			return fmt.Errorf(
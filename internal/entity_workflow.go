@@ -0,0 +1,198 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const (
+	defaultEntityMaxCommandsPerRun = 1000
+	defaultEntityMaxHistoryLength  = 10000
+	defaultEntityStateQueryType    = "state"
+)
+
+type (
+	// EntityWorkflowOptions configure an entity workflow created by NewEntityWorkflow.
+	EntityWorkflowOptions struct {
+		// MaxCommandsPerRun - The number of commands processed by a single run before the entity continues as new
+		// carrying its state.
+		// Optional: default 1000.
+		MaxCommandsPerRun int
+
+		// MaxHistoryLength - The number of events in the history of a single run after which the entity continues as
+		// new carrying its state. It is checked before every command, so handlers that run activities or timers don't
+		// grow the history of a run up to the limits of the server before MaxCommandsPerRun is reached.
+		// Optional: default 10000.
+		MaxHistoryLength int
+
+		// StateQueryType - The query type that returns the current state of the entity.
+		// Optional: default "state".
+		StateQueryType string
+	}
+
+	// EntityCommandHandler applies a single command to the state of an entity. The command is the encoded signal
+	// payload, use command.Get() to decode it. Returning an error stops the entity and fails the workflow with it.
+	EntityCommandHandler func(ctx Context, command Value) error
+
+	// EntityWorkflow runs the loop of a long-lived workflow that owns the state of a single entity and processes
+	// commands sent to it as signals. See NewEntityWorkflow.
+	EntityWorkflow interface {
+		// OnCommand registers the handler for the command with the given name. The command name is the name of the
+		// signal carrying it.
+		OnCommand(name string, handler EntityCommandHandler) EntityWorkflow
+
+		// Run processes commands until ctx is canceled or a handler returns an error. Once MaxCommandsPerRun
+		// commands were processed or the history reached MaxHistoryLength events, and no more commands are
+		// buffered, it returns a *ContinueAsNewError that starts workflowFn again with the current state as its only
+		// argument.
+		Run(ctx Context, workflowFn interface{}) error
+	}
+
+	entityCommand struct {
+		name    string
+		handler EntityCommandHandler
+	}
+
+	entityWorkflowImpl struct {
+		statePtr interface{}
+		options  EntityWorkflowOptions
+		commands []*entityCommand // in registration order, which keeps the signal loop deterministic
+	}
+)
+
+// NewEntityWorkflow creates an EntityWorkflow for the state pointed to by statePtr. The workflow function receives the
+// state as its argument, so a new run continues from the state the previous run ended with:
+//  func AccountWorkflow(ctx workflow.Context, state Account) error {
+//    return workflow.NewEntityWorkflow(&state, workflow.EntityWorkflowOptions{}).
+//      OnCommand("deposit", func(ctx workflow.Context, command encoded.Value) error {
+//        var amount int
+//        if err := command.Get(&amount); err != nil {
+//          return err
+//        }
+//        state.Balance += amount
+//        return nil
+//      }).
+//      Run(ctx, AccountWorkflow)
+//  }
+// Handlers are invoked one at a time, in the order the commands were received, so they may mutate the state without
+// synchronization. As any other workflow code, handlers must be deterministic.
+func NewEntityWorkflow(statePtr interface{}, options EntityWorkflowOptions) EntityWorkflow {
+	if rv := reflect.ValueOf(statePtr); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		panic("statePtr must be a non nil pointer")
+	}
+	if options.MaxCommandsPerRun <= 0 {
+		options.MaxCommandsPerRun = defaultEntityMaxCommandsPerRun
+	}
+	if options.MaxHistoryLength <= 0 {
+		options.MaxHistoryLength = defaultEntityMaxHistoryLength
+	}
+	if options.StateQueryType == "" {
+		options.StateQueryType = defaultEntityStateQueryType
+	}
+	return &entityWorkflowImpl{statePtr: statePtr, options: options}
+}
+
+func (e *entityWorkflowImpl) OnCommand(name string, handler EntityCommandHandler) EntityWorkflow {
+	for _, c := range e.commands {
+		if c.name == name {
+			panic(fmt.Sprintf("handler for command %v is already registered", name))
+		}
+	}
+	e.commands = append(e.commands, &entityCommand{name: name, handler: handler})
+	return e
+}
+
+func (e *entityWorkflowImpl) Run(ctx Context, workflowFn interface{}) error {
+	if len(e.commands) == 0 {
+		return errors.New("no command handler is registered")
+	}
+	state := reflect.ValueOf(e.statePtr).Elem()
+	if err := SetQueryHandler(ctx, e.options.StateQueryType, func() (interface{}, error) {
+		return state.Interface(), nil
+	}); err != nil {
+		return err
+	}
+
+	dc := getDataConverterFromWorkflowContext(ctx)
+	var handlerErr error
+	processed := 0
+	apply := func(c *entityCommand, ch Channel) {
+		v, _, _ := ch.(*channelImpl).receiveAsyncImpl(nil)
+		data, ok := v.([]byte)
+		if !ok {
+			// value was sent to the signal channel from within the workflow
+			var err error
+			if data, err = encodeArg(dc, v); err != nil {
+				handlerErr = err
+				return
+			}
+		}
+		processed++
+		handlerErr = c.handler(ctx, newEncodedValue(data, dc))
+	}
+
+	selector := NewNamedSelector(ctx, "entity-commands")
+	channels := make([]Channel, len(e.commands))
+	for i, c := range e.commands {
+		command := c
+		channels[i] = GetSignalChannel(ctx, command.name)
+		selector.AddReceive(channels[i], func(ch Channel, more bool) {
+			apply(command, ch)
+		})
+	}
+	canceled := false
+	if cancelCh := ctx.Done(); cancelCh != nil {
+		selector.AddReceive(cancelCh, func(c Channel, more bool) {
+			canceled = true
+		})
+	}
+
+	env := getWorkflowEnvironment(ctx)
+	for processed < e.options.MaxCommandsPerRun && env.GetHistoryLength() < int64(e.options.MaxHistoryLength) {
+		selector.Select(ctx)
+		if canceled {
+			return ctx.Err()
+		}
+		if handlerErr != nil {
+			return handlerErr
+		}
+	}
+
+	// Drain commands that were already delivered, they would be lost by continue as new otherwise.
+	for drained := true; drained; {
+		drained = false
+		for i, c := range e.commands {
+			if channels[i].Len() == 0 {
+				continue
+			}
+			apply(c, channels[i])
+			if handlerErr != nil {
+				return handlerErr
+			}
+			drained = true
+		}
+	}
+	return NewContinueAsNewError(ctx, workflowFn, state.Interface())
+}
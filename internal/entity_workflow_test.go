@@ -0,0 +1,130 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+type testAccount struct {
+	Balance int
+}
+
+func testAccountWorkflow(ctx Context, state testAccount) error {
+	return NewEntityWorkflow(&state, EntityWorkflowOptions{MaxCommandsPerRun: 3}).
+		OnCommand("deposit", func(ctx Context, command Value) error {
+			var amount int
+			if err := command.Get(&amount); err != nil {
+				return err
+			}
+			state.Balance += amount
+			return nil
+		}).
+		OnCommand("withdraw", func(ctx Context, command Value) error {
+			var amount int
+			if err := command.Get(&amount); err != nil {
+				return err
+			}
+			if amount > state.Balance {
+				return errors.New("insufficient balance")
+			}
+			state.Balance -= amount
+			return nil
+		}).
+		Run(ctx, testAccountWorkflow)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_EntityWorkflowStateQueryAndContinueAsNew() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testAccountWorkflow)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("deposit", 10)
+		env.SignalWorkflow("withdraw", 3)
+	}, time.Minute)
+	env.RegisterDelayedCallback(func() {
+		encoded, err := env.QueryWorkflow(defaultEntityStateQueryType)
+		s.NoError(err)
+		var state testAccount
+		s.NoError(encoded.Get(&state))
+		s.Equal(7, state.Balance)
+
+		// the third command reaches MaxCommandsPerRun
+		env.SignalWorkflow("deposit", 6)
+	}, 2*time.Minute)
+
+	env.ExecuteWorkflow(testAccountWorkflow, testAccount{})
+	s.True(env.IsWorkflowCompleted())
+	continueAsNewErr, ok := env.GetWorkflowError().(*ContinueAsNewError)
+	s.Require().True(ok, "%v", env.GetWorkflowError())
+	s.Equal([]interface{}{testAccount{Balance: 13}}, continueAsNewErr.Args())
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_EntityWorkflowHandlerError() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testAccountWorkflow)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("withdraw", 3)
+	}, time.Minute)
+
+	env.ExecuteWorkflow(testAccountWorkflow, testAccount{})
+	s.True(env.IsWorkflowCompleted())
+	s.Error(env.GetWorkflowError())
+	s.Contains(env.GetWorkflowError().Error(), "insufficient balance")
+}
+
+func testTimerAccountWorkflow(ctx Context, state testAccount) error {
+	return NewEntityWorkflow(&state, EntityWorkflowOptions{MaxHistoryLength: 20}).
+		OnCommand("deposit", func(ctx Context, command Value) error {
+			var amount int
+			if err := command.Get(&amount); err != nil {
+				return err
+			}
+			// every command adds a timer to the history
+			if err := Sleep(ctx, time.Second); err != nil {
+				return err
+			}
+			state.Balance += amount
+			return nil
+		}).
+		Run(ctx, testTimerAccountWorkflow)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_EntityWorkflowMaxHistoryLength() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testTimerAccountWorkflow)
+	for i := 1; i <= 5; i++ {
+		amount := i
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow("deposit", amount)
+		}, time.Duration(i)*time.Minute)
+	}
+
+	env.ExecuteWorkflow(testTimerAccountWorkflow, testAccount{})
+	s.True(env.IsWorkflowCompleted())
+	continueAsNewErr, ok := env.GetWorkflowError().(*ContinueAsNewError)
+	s.Require().True(ok, "%v", env.GetWorkflowError())
+	// the history reaches 20 events with the second command, far below the default MaxCommandsPerRun
+	s.Equal([]interface{}{testAccount{Balance: 3}}, continueAsNewErr.Args())
+}
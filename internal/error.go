@@ -152,6 +152,13 @@ var ErrNoData = errors.New("no data available")
 // ErrTooManyArg is returned when trying to extract strong typed data with more arguments than available data.
 var ErrTooManyArg = errors.New("too many arguments")
 
+// ErrActivityLimitExceeded is returned by the future of ExecuteActivity when the workflow run has already scheduled
+// the number of activities allowed by WithActivityLimit.
+var ErrActivityLimitExceeded = errors.New("ActivityLimitExceeded: workflow reached the limit of scheduled activities")
+
+// ErrInvalidActivityParameters is wrapped by the error ExecuteActivityParameters.Validate returns.
+var ErrInvalidActivityParameters = errors.New("invalid activity parameters")
+
 // ErrActivityResultPending is returned from activity's implementation to indicate the activity is not completed when
 // activity method returns. Activity needs to be completed by Client.CompleteActivity() separately. For example, if an
 // activity require human interaction (like approve an expense report), the activity could return activity.ErrResultPending
@@ -206,6 +213,33 @@ func IsCanceledError(err error) bool {
 	return ok
 }
 
+// GetLastHeartbeatDetails returns the encoded details of the last heartbeat that an activity recorded before it timed
+// out, if err is the *TimeoutError of such an activity. The details are recorded by the server along with the timeout
+// and are part of the workflow history, so they are the same on replay. When the activity is retried by the server
+// because of a RetryPolicy, each new attempt reads the details of the previous one with activity.GetHeartbeatDetails,
+// and the workflow only receives the error, with the details of the last attempt, once the retries are exhausted.
+// The workflow can then pass the details to a new invocation of the activity to resume from the last checkpoint:
+//  err := workflow.ExecuteActivity(ctx, processFile, path, nil).Get(ctx, nil)
+//  if details, ok := workflow.GetLastHeartbeatDetails(err); ok {
+//    err = workflow.ExecuteActivity(ctx, processFile, path, details).Get(ctx, nil)
+//  }
+// The details are encoded with the data converter used by the activity worker.
+func GetLastHeartbeatDetails(err error) ([]byte, bool) {
+	timeoutErr, ok := err.(*TimeoutError)
+	if !ok || !timeoutErr.HasDetails() {
+		return nil, false
+	}
+	switch details := timeoutErr.details.(type) {
+	case *EncodedValues:
+		return details.values, true
+	case ErrorDetailsValues:
+		// details of an error created in process, like by a mock of the test environment
+		data, err := encodeArgs(nil, details)
+		return data, err == nil
+	}
+	return nil, false
+}
+
 // NewContinueAsNewError creates ContinueAsNewError instance
 // If the workflow main function returns this error then the current execution is ended and
 // the new execution with same workflow ID is started automatically with options
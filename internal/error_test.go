@@ -149,6 +149,24 @@ func testTimeoutErrorDetails(t *testing.T, timeoutType shared.TimeoutType) {
 	data := ""
 	require.NoError(t, err.Details(&data))
 	require.Equal(t, testErrorDetails1, data)
+	details, ok := GetLastHeartbeatDetails(actualErr)
+	require.True(t, ok)
+	require.Equal(t, encodedDetails1, details)
+}
+
+func Test_GetLastHeartbeatDetails(t *testing.T) {
+	details, ok := GetLastHeartbeatDetails(NewHeartbeatTimeoutError(testErrorDetails1))
+	require.True(t, ok)
+	var data string
+	require.NoError(t, getDefaultDataConverter().FromData(details, &data))
+	require.Equal(t, testErrorDetails1, data)
+
+	_, ok = GetLastHeartbeatDetails(NewTimeoutError(shared.TimeoutTypeStartToClose))
+	require.False(t, ok)
+	_, ok = GetLastHeartbeatDetails(NewCustomError("reason", testErrorDetails1))
+	require.False(t, ok)
+	_, ok = GetLastHeartbeatDetails(nil)
+	require.False(t, ok)
 }
 
 func Test_CustomError(t *testing.T) {
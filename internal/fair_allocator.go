@@ -0,0 +1,131 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// FairAllocator shares a fixed number of tokens among the coroutines of a workflow, granting them in the order
+	// they were requested. See NewFairAllocator.
+	FairAllocator interface {
+		// Acquire blocks until weight tokens are granted to the caller. Requests are granted strictly in the order
+		// they were made: a request that does not fit yet holds back the ones made after it, even if those would fit,
+		// so that large requests are not starved by a stream of small ones. It returns CanceledError, without
+		// acquiring any token, if ctx is canceled first.
+		// Acquire panics if weight is not positive or exceeds the capacity of the allocator.
+		Acquire(ctx Context, weight int) error
+		// Release returns weight tokens to the allocator and grants them to the waiting requests. It panics if more
+		// tokens are released than were acquired.
+		Release(weight int)
+		// Available returns the number of tokens that are not acquired.
+		Available() int
+	}
+
+	fairAllocatorImpl struct {
+		capacity  int
+		available int
+		waiters   []*fairAllocatorWaiter // pending requests, in the order they were made
+	}
+
+	fairAllocatorWaiter struct {
+		weight  int
+		granted Channel
+	}
+)
+
+// NewFairAllocator creates a FairAllocator with capacity tokens:
+//  allocator := workflow.NewFairAllocator(ctx, 10)
+//  if err := allocator.Acquire(ctx, 3); err != nil {
+//    return err
+//  }
+//  defer allocator.Release(3)
+// NewFairAllocator panics if capacity is not positive.
+func NewFairAllocator(ctx Context, capacity int) FairAllocator {
+	if capacity <= 0 {
+		panic("non-positive capacity for NewFairAllocator")
+	}
+	return &fairAllocatorImpl{capacity: capacity, available: capacity}
+}
+
+func (a *fairAllocatorImpl) Acquire(ctx Context, weight int) error {
+	if weight <= 0 || weight > a.capacity {
+		panic("FairAllocator.Acquire weight must be positive and not exceed the capacity")
+	}
+	if len(a.waiters) == 0 && weight <= a.available {
+		a.available -= weight
+		return nil
+	}
+	w := &fairAllocatorWaiter{weight: weight, granted: NewBufferedChannel(ctx, 1)}
+	a.waiters = append(a.waiters, w)
+
+	granted := false
+	selector := NewSelector(ctx).AddReceive(w.granted, func(c Channel, more bool) {
+		c.Receive(ctx, nil)
+		granted = true
+	})
+	if done := ctx.Done(); done != nil {
+		selector.AddReceive(done, func(c Channel, more bool) {})
+	}
+	selector.Select(ctx)
+	if granted {
+		return nil
+	}
+	if w.granted.ReceiveAsync(nil) {
+		// granted after the cancellation was delivered, give the tokens back
+		a.available += w.weight
+	} else {
+		a.remove(w)
+	}
+	// the canceled request may have been holding back the ones after it
+	a.grant()
+	return ctx.Err()
+}
+
+func (a *fairAllocatorImpl) Release(weight int) {
+	if weight < 0 || a.available+weight > a.capacity {
+		panic("FairAllocator.Release of tokens that were not acquired")
+	}
+	a.available += weight
+	a.grant()
+}
+
+func (a *fairAllocatorImpl) Available() int {
+	return a.available
+}
+
+// grant hands tokens to the waiters at the head of the queue as long as they fit.
+func (a *fairAllocatorImpl) grant() {
+	for len(a.waiters) > 0 && a.waiters[0].weight <= a.available {
+		w := a.waiters[0]
+		a.waiters[0] = nil
+		a.waiters = a.waiters[1:]
+		a.available -= w.weight
+		w.granted.SendAsync(true)
+	}
+}
+
+func (a *fairAllocatorImpl) remove(w *fairAllocatorWaiter) {
+	for i, waiter := range a.waiters {
+		if waiter == w {
+			a.waiters = append(a.waiters[:i], a.waiters[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFairAllocatorWorkflow(ctx Context) ([]string, error) {
+	allocator := NewFairAllocator(ctx, 4)
+	granted := make(map[string]int)
+	var history []string
+	wg := NewWaitGroup(ctx)
+	// small requests keep the allocator busy, the large one must still get its turn every round
+	for _, w := range []struct {
+		name   string
+		weight int
+	}{{"small1", 1}, {"small2", 1}, {"large", 4}, {"small3", 2}} {
+		w := w
+		wg.Add(1)
+		Go(ctx, func(ctx Context) {
+			defer wg.Done()
+			for round := 0; round < 5; round++ {
+				if err := allocator.Acquire(ctx, w.weight); err != nil {
+					panic(err)
+				}
+				granted[w.name]++
+				history = append(history, w.name)
+				_ = Sleep(ctx, time.Second)
+				allocator.Release(w.weight)
+			}
+		})
+	}
+	wg.Wait(ctx)
+	for _, name := range []string{"small1", "small2", "large", "small3"} {
+		if granted[name] != 5 {
+			return nil, fmt.Errorf("%v was granted %v times", name, granted[name])
+		}
+	}
+	if allocator.Available() != 4 {
+		return nil, fmt.Errorf("%v tokens available after all releases", allocator.Available())
+	}
+	return history[:8], nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_FairAllocator() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testFairAllocatorWorkflow)
+	env.ExecuteWorkflow(testFairAllocatorWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var history []string
+	s.NoError(env.GetWorkflowResult(&history))
+	// the large request blocks the requests made after it until it is granted, and then gets all the tokens
+	s.Equal([]string{"small1", "small2", "large", "small3", "small1", "small2", "large", "small3"}, history)
+}
+
+func TestFairAllocator_Cancel(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		allocator := NewFairAllocator(ctx, 2)
+		require.NoError(t, allocator.Acquire(ctx, 2))
+
+		cancelCtx, cancel := WithCancel(ctx)
+		var errs []error
+		Go(ctx, func(ctx Context) {
+			errs = append(errs, allocator.Acquire(cancelCtx, 2))
+		})
+		var smallErr error
+		smallDone := false
+		Go(ctx, func(ctx Context) {
+			smallErr = allocator.Acquire(ctx, 1)
+			smallDone = true
+		})
+		cancel()
+		_ = Await(ctx, func() bool { return len(errs) == 1 })
+		require.Equal(t, ErrCanceled, errs[0])
+		require.False(t, smallDone, "the small request waits for the tokens held by the first caller")
+
+		allocator.Release(2)
+		_ = Await(ctx, func() bool { return smallDone })
+		require.NoError(t, smallErr)
+		require.Equal(t, 1, allocator.Available())
+
+		require.Panics(t, func() { allocator.Release(2) })
+		require.Panics(t, func() { _ = allocator.Acquire(ctx, 3) })
+		require.Panics(t, func() { NewFairAllocator(ctx, 0) })
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+}
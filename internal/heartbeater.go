@@ -0,0 +1,126 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Heartbeater throttles the heartbeats of an activity. See NewHeartbeater.
+type Heartbeater struct {
+	ctx      context.Context
+	interval time.Duration
+
+	sync.Mutex
+	lastSent       time.Time
+	pending        *[]byte // latest details that were not sent yet
+	flushScheduled bool
+	err            error // cancellation reported by the server
+}
+
+// NewHeartbeater creates a Heartbeater for the activity of ctx that sends at most one heartbeat per interval, so that
+// activity code can call Beat as often as it likes, for example on every iteration of its main loop:
+//  heartbeater := activity.NewHeartbeater(ctx, 10*time.Second)
+//  for _, item := range items {
+//    if err := heartbeater.Beat(progress(item)); err != nil {
+//      return err
+//    }
+//    ...
+//  }
+// Pick an interval shorter than the HeartbeatTimeout of the activity. NewHeartbeater panics if interval is not positive.
+func NewHeartbeater(ctx context.Context, interval time.Duration) *Heartbeater {
+	if interval <= 0 {
+		panic("non-positive interval for NewHeartbeater")
+	}
+	getActivityEnv(ctx) // panics if ctx is not an activity context
+	return &Heartbeater{ctx: ctx, interval: interval}
+}
+
+// Beat records details as the latest progress of the activity. The heartbeat is sent right away if none was sent
+// during the last interval. Otherwise it is sent in the background once the interval elapsed, with the details of the
+// latest call to Beat. Details are encoded like the ones of RecordActivityHeartbeat, so they can be read back with
+// GetHeartbeatDetails into a []byte.
+// Beat returns a CanceledError once the server reported that the activity was canceled, and the error of the activity
+// context once it is done. The activity should then stop its work and return the error. Other heartbeat failures are
+// only logged, like for RecordActivityHeartbeat.
+func (h *Heartbeater) Beat(details []byte) error {
+	h.Lock()
+	defer h.Unlock()
+	if h.err != nil {
+		return h.err
+	}
+	if err := h.ctx.Err(); err != nil {
+		return err
+	}
+	elapsed := time.Since(h.lastSent)
+	if elapsed >= h.interval {
+		h.sendLocked(details)
+		return h.err
+	}
+	h.pending = &details
+	if !h.flushScheduled {
+		h.flushScheduled = true
+		go h.flushAfter(h.interval - elapsed)
+	}
+	return nil
+}
+
+// flushAfter sends the pending details after d, unless the activity context is done first.
+func (h *Heartbeater) flushAfter(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-h.ctx.Done():
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	h.flushScheduled = false
+	if h.pending != nil && h.err == nil && h.ctx.Err() == nil {
+		h.sendLocked(*h.pending)
+	}
+}
+
+func (h *Heartbeater) sendLocked(details []byte) {
+	h.pending = nil
+	h.lastSent = time.Now()
+	env := getActivityEnv(h.ctx)
+	if env.isLocalActivity {
+		// no-op for local activity
+		return
+	}
+	data, err := encodeArgs(getDataConverterFromActivityCtx(h.ctx), []interface{}{details})
+	if err == nil {
+		// the Heartbeater does its own batching
+		err = env.serviceInvoker.Heartbeat(data, true)
+	}
+	if canceledErr, ok := err.(*CanceledError); ok {
+		h.err = canceledErr
+	} else if err != nil {
+		GetActivityLogger(h.ctx).Debug("Heartbeater.Beat With Error:", zap.Error(err))
+	}
+}
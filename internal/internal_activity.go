@@ -64,6 +64,10 @@ type (
 		WaitForCancellation           bool
 		OriginalTaskListName          string
 		RetryPolicy                   *shared.RetryPolicy
+		OnComplete                    func(result []byte, err error)
+		// CancellationGracePeriodSeconds bounds how long a canceled activity is waited for when WaitForCancellation
+		// is set, 0 means no bound.
+		CancellationGracePeriodSeconds int32
 	}
 
 	localActivityOptions struct {
@@ -227,6 +227,133 @@ func TestBufferedChannelGet(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestChannelLen(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c1 := NewChannel(ctx)
+		c2 := NewBufferedChannel(ctx, 3)
+		require.Equal(t, 0, c1.Len())
+		require.Equal(t, 0, c2.Len())
+
+		Go(ctx, func(ctx Context) {
+			c1.Send(ctx, "blocked")
+		})
+		c2.Send(ctx, "one")
+		c2.Send(ctx, "two")
+		require.Equal(t, 2, c2.Len())
+		require.Equal(t, 0, c1.Len())
+
+		var v string
+		c2.Receive(ctx, &v)
+		require.Equal(t, "one", v)
+		require.Equal(t, 1, c2.Len())
+		require.Equal(t, 0, c1.Len())
+		c1.Receive(ctx, &v)
+		require.Equal(t, "blocked", v)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
+func TestChannelReceiveAll(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewBufferedChannel(ctx, 3)
+		require.Equal(t, []interface{}{}, c.ReceiveAll(ctx))
+
+		c.Send(ctx, "one")
+		c.Send(ctx, "two")
+		require.Equal(t, []interface{}{"one", "two"}, c.ReceiveAll(ctx))
+		require.Equal(t, 0, c.Len())
+		require.Equal(t, []interface{}{}, c.ReceiveAll(ctx))
+
+		c.Send(ctx, "three")
+		c.Close()
+		require.Equal(t, []interface{}{"three"}, c.ReceiveAll(ctx))
+		require.Equal(t, []interface{}{}, c.ReceiveAll(ctx))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
+func TestChannelReceiveFIFO(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewChannel(ctx)
+		ack := NewChannel(ctx)
+		for i := 1; i <= 3; i++ {
+			name := fmt.Sprintf("r%v", i)
+			Go(ctx, func(ctx Context) {
+				for {
+					var v int
+					if !c.Receive(ctx, &v) {
+						return
+					}
+					history = append(history, fmt.Sprintf("%v-%v", name, v))
+					ack.Send(ctx, nil)
+				}
+			})
+		}
+		// started after the receivers, so all of them are blocked on Receive when the first value is sent
+		Go(ctx, func(ctx Context) {
+			for i := 0; i < 6; i++ {
+				c.Send(ctx, i)
+				ack.Receive(ctx, nil)
+			}
+			c.Close()
+		})
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+	require.EqualValues(t, []string{"r1-0", "r2-1", "r3-2", "r1-3", "r2-4", "r3-5"}, history)
+}
+
+func TestChannelDrain(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewBufferedChannel(ctx, 3)
+		require.Nil(t, Drain(c))
+
+		c.Send(ctx, "one")
+		c.Send(ctx, "two")
+		c.Send(ctx, "three")
+		var v string
+		c.Receive(ctx, &v)
+		require.Equal(t, "one", v)
+
+		// the channel is still open, Drain returns what is buffered now
+		require.Equal(t, []interface{}{"two", "three"}, Drain(c))
+		require.Nil(t, Drain(c))
+
+		c.SendAsync("four")
+		c.Close()
+		require.Equal(t, []interface{}{"four"}, Drain(c))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
+func TestCollectUniqueN(t *testing.T) {
+	keyOf := func(v interface{}) string { return v.(string) }
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewChannel(ctx)
+		Go(ctx, func(ctx Context) {
+			for _, v := range []string{"b", "a", "b", "b", "c", "a", "d", "e"} {
+				c.Send(ctx, v)
+			}
+			c.Close()
+		})
+		require.Equal(t, []interface{}{"b", "a", "c"}, CollectUniqueN(ctx, c, 3, keyOf))
+		// keys are only deduplicated within a call
+		require.Equal(t, []interface{}{"a", "d", "e"}, CollectUniqueN(ctx, c, 5, keyOf), "closed channel")
+
+		ctx, cancel := WithCancel(ctx)
+		Go(ctx, func(ctx Context) {
+			cancel()
+		})
+		require.Empty(t, CollectUniqueN(ctx, NewChannel(ctx), 1, keyOf), "canceled context")
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+}
+
 func TestNotBlockingSelect(t *testing.T) {
 	var history []string
 	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
@@ -576,6 +703,118 @@ func TestChannelClose(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestChannelCloseOnce(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewChannel(ctx)
+		GoNamed(ctx, "receiver", func(ctx Context) {
+			more := c.Receive(ctx, nil)
+			history = append(history, fmt.Sprintf("receiver more=%v", more))
+		})
+		for i := 0; i < 2; i++ {
+			GoNamed(ctx, fmt.Sprintf("closer%v", i), func(ctx Context) {
+				history = append(history, fmt.Sprintf("closed=%v", c.CloseOnce()))
+			})
+		}
+		Go(ctx, func(ctx Context) {
+			c.Close()
+			history = append(history, "closed again")
+			defer func() {
+				require.NotNil(t, recover(), "send to a closed channel must panic")
+			}()
+			c.SendAsync("foo")
+		})
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+	require.EqualValues(t, []string{"closed=true", "closed=false", "closed again", "receiver more=false"}, history)
+}
+
+func TestReceiveAsyncDrainAfterClose(t *testing.T) {
+	type received struct {
+		value    interface{}
+		ok, more bool
+	}
+	testCases := []struct {
+		name   string
+		size   int
+		values []string
+		close  bool
+		// closeWhileSelecting closes the channel while a Selector is blocked on it
+		closeWhileSelecting bool
+		expected            []received
+	}{
+		{
+			name:     "empty open",
+			size:     2,
+			expected: []received{{nil, false, true}, {nil, false, true}},
+		},
+		{
+			name:     "empty closed",
+			size:     2,
+			close:    true,
+			expected: []received{{nil, false, false}, {nil, false, false}},
+		},
+		{
+			name:     "buffered open",
+			size:     2,
+			values:   []string{"a", "b"},
+			expected: []received{{"a", true, true}, {"b", true, true}, {nil, false, true}},
+		},
+		{
+			name:     "buffered closed",
+			size:     2,
+			values:   []string{"a", "b"},
+			close:    true,
+			expected: []received{{"a", true, true}, {"b", true, true}, {nil, false, false}, {nil, false, false}},
+		},
+		{
+			name:     "unbuffered closed",
+			close:    true,
+			expected: []received{{nil, false, false}},
+		},
+		{
+			name:                "closed while selecting",
+			size:                2,
+			closeWhileSelecting: true,
+			expected:            []received{{nil, false, false}, {nil, false, false}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var result []received
+			d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+				c := NewBufferedChannel(ctx, tc.size)
+				for _, v := range tc.values {
+					c.Send(ctx, v)
+				}
+				if tc.close {
+					c.Close()
+				}
+				if tc.closeWhileSelecting {
+					Go(ctx, func(ctx Context) {
+						c.Close()
+					})
+					var selected bool
+					NewSelector(ctx).AddReceive(c, func(c Channel, more bool) {
+						selected = true
+						require.False(t, more)
+					}).Select(ctx)
+					require.True(t, selected)
+				}
+				for range tc.expected {
+					var v interface{}
+					ok, more := c.ReceiveAsyncWithMoreFlag(&v)
+					result = append(result, received{v, ok, more})
+				}
+			})
+			require.NoError(t, d.ExecuteUntilAllBlocked())
+			require.True(t, d.IsDone(), d.StackTrace())
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
 func TestSendClosedChannel(t *testing.T) {
 	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
 		defer func() {
@@ -663,6 +902,30 @@ func TestDispatchClose(t *testing.T) {
 	}
 }
 
+func TestGetStackTrace(t *testing.T) {
+	var stack string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewNamedChannel(ctx, "forever_blocked")
+		ready := NewBufferedChannel(ctx, 1)
+		GoNamed(ctx, "poller", func(ctx Context) {
+			ready.SendAsync(true)
+			c.Receive(ctx, nil) // blocked forever
+		})
+		ready.Receive(ctx, nil)
+		stack = GetStackTrace(ctx)
+		c.Receive(ctx, nil) // blocked forever
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.False(t, d.IsDone())
+	lines := strings.Split(stack, "\n")
+	// 2 coroutines (3 lines each) + 1 nl
+	require.EqualValues(t, 2*3+1, len(lines), stack)
+	require.Equal(t, "coroutine 1 [running]:", lines[0])
+	require.Contains(t, lines[1], "cadence/internal.TestGetStackTrace.func1", stack)
+	require.Equal(t, "coroutine poller [blocked on forever_blocked.Receive]:", lines[4])
+	d.Close()
+}
+
 func TestPanic(t *testing.T) {
 	var history []string
 	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
@@ -814,6 +1077,57 @@ func TestFutureFail(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestFutureSetCancel(t *testing.T) {
+	var history []string
+	var f Future
+	var s Settable
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		f, s = NewFuture(ctx)
+		Go(ctx, func(ctx Context) {
+			history = append(history, "child-start")
+			var v string
+			err := f.Get(ctx, &v)
+			require.Equal(t, ErrCanceled, err)
+			require.True(t, IsCanceledError(err))
+			history = append(history, fmt.Sprintf("future-get-canceled-%v", IsCanceledError(err)))
+		})
+		history = append(history, "root-end")
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.False(t, d.IsDone(), fmt.Sprintf("%v", d.StackTrace()))
+	history = append(history, "future-cancel")
+	s.SetCancel()
+	require.True(t, f.IsReady())
+	require.Panics(t, func() { s.SetCancel() })
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+
+	expected := []string{
+		"root-end",
+		"child-start",
+		"future-cancel",
+		"future-get-canceled-true",
+	}
+	require.EqualValues(t, expected, history)
+}
+
+func TestNamedFutureStackTrace(t *testing.T) {
+	var s Settable
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		var f Future
+		f, s = NewNamedFuture(ctx, "approval")
+		var v string
+		require.NoError(t, f.Get(ctx, &v))
+		require.Equal(t, "approved", v)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.False(t, d.IsDone())
+	require.Contains(t, d.StackTrace(), "coroutine 1 [blocked on approval.Receive]:")
+	s.Set("approved", nil)
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
 func TestFutureSet(t *testing.T) {
 	var history []string
 	var f1, f2 Future
@@ -955,6 +1269,65 @@ func TestFutureChain(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestFutureChainWithTransform(t *testing.T) {
+	var history []string
+	var cs1, cs2, cs3 Settable
+
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		transform := func(v interface{}) (interface{}, error) {
+			history = append(history, fmt.Sprintf("transform-%v", v))
+			if v == "bad" {
+				return "ignored", errors.New("transform-error")
+			}
+			return strings.ToUpper(v.(string)), nil
+		}
+		var cf1, cf2, cf3 Future
+		cf1, cs1 = NewFuture(ctx)
+		cf2, cs2 = NewFuture(ctx)
+		cf3, cs3 = NewFuture(ctx)
+		f1, s1 := NewFuture(ctx)
+		s1.ChainWithTransform(cf1, transform)
+		f2, s2 := NewFuture(ctx)
+		s2.ChainWithTransform(cf2, transform)
+		f3, s3 := NewFuture(ctx)
+		s3.ChainWithTransform(cf3, transform)
+
+		var v string
+		err := f1.Get(ctx, &v)
+		history = append(history, fmt.Sprintf("f1-%v-%v", v, err))
+		v = ""
+		err = f2.Get(ctx, &v)
+		history = append(history, fmt.Sprintf("f2-%v-%v", v, err))
+		v = ""
+		err = f3.Get(ctx, &v)
+		history = append(history, fmt.Sprintf("f3-%v-%v", v, err))
+
+		// chaining a future that is already ready
+		f4, s4 := NewFuture(ctx)
+		s4.ChainWithTransform(cf1, transform)
+		err = f4.Get(ctx, &v)
+		history = append(history, fmt.Sprintf("f4-%v-%v", v, err))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.False(t, d.IsDone())
+	cs1.SetValue("value")
+	cs2.SetValue("bad")
+	cs3.Set("ignored", errors.New("source-error"))
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+
+	expected := []string{
+		"transform-value",
+		"transform-bad",
+		"f1-VALUE-<nil>",
+		"f2--transform-error",
+		"f3--source-error",
+		"transform-value",
+		"f4-VALUE-<nil>",
+	}
+	require.EqualValues(t, expected, history)
+}
+
 func TestSelectFuture(t *testing.T) {
 	var history []string
 	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
@@ -1175,6 +1548,272 @@ func TestSelectFuture_WithBatchSets(t *testing.T) {
 	require.EqualValues(t, expected, history)
 }
 
+func TestSelectRemoveCases(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c1 := NewBufferedChannel(ctx, 1)
+		c2 := NewBufferedChannel(ctx, 1)
+		future1, settable1 := NewFuture(ctx)
+		future2, _ := NewFuture(ctx)
+
+		s := NewSelector(ctx)
+		s.
+			AddReceive(c1, func(c Channel, more bool) {
+				var v string
+				c.Receive(ctx, &v)
+				history = append(history, fmt.Sprintf("c1-%v", v))
+			}).
+			AddReceive(c2, func(c Channel, more bool) {
+				var v string
+				c.Receive(ctx, &v)
+				history = append(history, fmt.Sprintf("c2-%v", v))
+			}).
+			AddFuture(future1, func(f Future) {
+				history = append(history, "future1")
+			})
+		s.AddDefault(func() { history = append(history, "default") })
+
+		// removing cases that were never added is a no-op
+		s.RemoveFuture(future2).RemoveReceive(NewChannel(ctx))
+
+		s.RemoveReceive(c1).RemoveFuture(future1)
+		c1.Send(ctx, "one")
+		settable1.SetValue(true)
+		s.Select(ctx)
+		c2.Send(ctx, "two")
+		s.Select(ctx)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+
+	expected := []string{
+		"default",
+		"c2-two",
+	}
+	require.EqualValues(t, expected, history)
+}
+
+func TestChainReadyFuture(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		ready, readySettable := NewFuture(ctx)
+		readySettable.SetValue("value")
+
+		f, s := NewFuture(ctx)
+		s.Chain(ready)
+		require.True(t, f.IsReady())
+		var v string
+		require.NoError(t, f.Get(ctx, &v))
+		history = append(history, "get-"+v)
+
+		// futures chained to f before it was chained to the ready future are resolved as well
+		f2, s2 := NewFuture(ctx)
+		chained, chainedSettable := NewFuture(ctx)
+		chainedSettable.Chain(f2)
+		s2.Chain(ready)
+		NewSelector(ctx).AddFuture(chained, func(f Future) {
+			var v string
+			require.NoError(t, f.Get(ctx, &v))
+			history = append(history, "select-"+v)
+		}).Select(ctx)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), strings.Join(history, "\n"))
+	require.EqualValues(t, []string{"get-value", "select-value"}, history)
+}
+
+func TestSelectIndex(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c1 := NewChannel(ctx)
+		c2 := NewBufferedChannel(ctx, 1)
+		future, settable := NewFuture(ctx)
+		s := NewSelector(ctx).
+			AddReceive(c1, func(c Channel, more bool) {
+				c.Receive(ctx, nil)
+				history = append(history, "c1")
+			}).
+			AddFuture(future, func(f Future) {
+				history = append(history, "future")
+			}).
+			AddSend(c2, "value", func() {
+				history = append(history, "c2")
+			})
+
+		// ready right away
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+		// blocked until another coroutine makes a case ready
+		Go(ctx, func(ctx Context) {
+			settable.SetValue(true)
+		})
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+		Go(ctx, func(ctx Context) {
+			c1.Send(ctx, "value")
+		})
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+
+		s.AddDefault(func() { history = append(history, "default") })
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+
+	expected := []string{
+		"c2",
+		"index-2",
+		"future",
+		"index-1",
+		"c1",
+		"index-0",
+		"default",
+		"index--1",
+	}
+	require.EqualValues(t, expected, history)
+}
+
+func TestSelectWake(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewChannel(ctx)
+		s := NewSelector(ctx).
+			AddReceive(c, func(c Channel, more bool) {
+				c.Receive(ctx, nil)
+				history = append(history, "c")
+			})
+
+		// no case is ever ready, Wake unblocks Select
+		Go(ctx, func(ctx Context) {
+			history = append(history, "wake")
+			s.Wake()
+			s.Wake()
+		})
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+
+		// a pending Wake returns the next Select with the wake case
+		s.AddWake(func() { history = append(history, "woken") })
+		s.Wake()
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+
+		// a ready case takes precedence and consumes the pending Wake
+		Go(ctx, func(ctx Context) {
+			c.Send(ctx, "value")
+		})
+		Go(ctx, func(ctx Context) {
+			s.Wake()
+			s.Wake()
+		})
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+		Go(ctx, func(ctx Context) {
+			c.Send(ctx, "value")
+		})
+		history = append(history, fmt.Sprintf("index-%v", s.SelectIndex(ctx)))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone(), d.StackTrace())
+
+	expected := []string{
+		"wake",
+		"index--1",
+		"woken",
+		"index--1",
+		"c",
+		"index-0",
+		"c",
+		"index-0",
+	}
+	require.EqualValues(t, expected, history)
+}
+
+func TestSelectHasPending(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c1 := NewBufferedChannel(ctx, 1)
+		c2 := NewBufferedChannel(ctx, 1)
+		future, settable := NewFuture(ctx)
+
+		s := NewSelector(ctx)
+		s.
+			AddReceive(c1, func(c Channel, more bool) {
+				var v string
+				c.Receive(ctx, &v)
+				history = append(history, fmt.Sprintf("c1-%v", v))
+			}).
+			AddFuture(future, func(f Future) {
+				history = append(history, "future")
+			})
+		s.AddDefault(func() { history = append(history, "default") })
+		history = append(history, fmt.Sprintf("pending-%v", s.HasPending()))
+
+		c1.SendAsync("one")
+		history = append(history, fmt.Sprintf("pending-%v", s.HasPending()))
+		s.Select(ctx)
+		history = append(history, fmt.Sprintf("pending-%v", s.HasPending()))
+
+		settable.SetValue(true)
+		history = append(history, fmt.Sprintf("pending-%v", s.HasPending()))
+		s.Select(ctx)
+		// the ready future was already consumed by the previous Select
+		history = append(history, fmt.Sprintf("pending-%v", s.HasPending()))
+
+		sendSelector := NewSelector(ctx).AddSend(c2, "two", func() {})
+		history = append(history, fmt.Sprintf("send-pending-%v", sendSelector.HasPending()))
+		c2.Send(ctx, "full")
+		history = append(history, fmt.Sprintf("send-pending-%v", sendSelector.HasPending()))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+
+	expected := []string{
+		"pending-false",
+		"pending-true",
+		"c1-one",
+		"pending-false",
+		"pending-true",
+		"future",
+		"pending-false",
+		"send-pending-true",
+		"send-pending-false",
+	}
+	require.EqualValues(t, expected, history)
+}
+
+func TestSelectDefault(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		c := NewBufferedChannel(ctx, 1)
+		s := NewSelector(ctx).AddReceive(c, func(c Channel, more bool) {
+			var v string
+			c.Receive(ctx, &v)
+			history = append(history, fmt.Sprintf("c-%v", v))
+		})
+		history = append(history, fmt.Sprintf("has-default-%v", s.HasDefault()))
+		s.SelectWithDefault(ctx, func() { history = append(history, "one-shot") })
+		history = append(history, fmt.Sprintf("has-default-%v", s.HasDefault()))
+
+		s.AddDefault(func() { history = append(history, "default") }).
+			AddDefault(func() { history = append(history, "replaced default") })
+		history = append(history, fmt.Sprintf("has-default-%v", s.HasDefault()))
+		s.SelectWithDefault(ctx, func() { history = append(history, "one-shot") })
+		s.Select(ctx)
+
+		c.SendAsync("one")
+		s.SelectWithDefault(ctx, func() { history = append(history, "one-shot") })
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+
+	expected := []string{
+		"has-default-false",
+		"one-shot",
+		"has-default-false",
+		"has-default-true",
+		"one-shot",
+		"replaced default",
+		"c-one",
+	}
+	require.EqualValues(t, expected, history)
+}
+
 func TestChainedFuture(t *testing.T) {
 	activityFn := func(arg int) (int, error) {
 		return arg, nil
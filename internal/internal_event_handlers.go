@@ -100,6 +100,8 @@ type (
 		openSessions      map[string]*SessionInfo
 
 		counterID         int32     // To generate sequence IDs for activity/timer etc.
+		decisionsStarted  int       // Number of decision task started events processed so far.
+		historyLength     int64     // Event ID of the last decision task started event processed.
 		currentReplayTime time.Time // Indicates current replay time of the decision.
 		currentLocalTime  time.Time // Local time when currentReplayTime was updated.
 
@@ -446,6 +448,14 @@ func (wc *workflowEnvironmentImpl) IsReplaying() bool {
 	return wc.isReplay
 }
 
+func (wc *workflowEnvironmentImpl) IsFirstDecision() bool {
+	return wc.decisionsStarted <= 1
+}
+
+func (wc *workflowEnvironmentImpl) GetHistoryLength() int64 {
+	return wc.historyLength
+}
+
 func (wc *workflowEnvironmentImpl) GenerateSequenceID() string {
 	return fmt.Sprintf("%d", wc.GenerateSequence())
 }
@@ -621,6 +631,14 @@ func (wc *workflowEnvironmentImpl) GetVersion(changeID string, minSupported, max
 	return version
 }
 
+func (wc *workflowEnvironmentImpl) GetVersions() map[string]Version {
+	versions := make(map[string]Version, len(wc.changeVersions))
+	for changeID, version := range wc.changeVersions {
+		versions[changeID] = version
+	}
+	return versions
+}
+
 func createSearchAttributesForChangeVersion(changeID string, version Version, existingChangeVersions map[string]Version) map[string]interface{} {
 	return map[string]interface{}{
 		CadenceChangeVersion: getChangeVersions(changeID, version, existingChangeVersions),
@@ -810,6 +828,8 @@ func (weh *workflowExecutionEventHandlerImpl) ProcessEvent(
 	case m.EventTypeDecisionTaskStarted:
 		// Set replay clock.
 		weh.SetCurrentReplayTime(time.Unix(0, event.GetTimestamp()))
+		weh.decisionsStarted++
+		weh.historyLength = event.GetEventId()
 		weh.workflowDefinition.OnDecisionTaskStarted()
 
 	case m.EventTypeDecisionTaskTimedOut:
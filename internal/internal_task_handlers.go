@@ -70,6 +70,7 @@ type (
 	// workflowTask wraps a decision task.
 	workflowTask struct {
 		task            *s.PollForDecisionTaskResponse
+		taskListKind    s.TaskListKind // kind of the task list the task was polled from
 		historyIterator HistoryIterator
 		doneCh          chan struct{}
 		laResultCh      chan *localActivityResult
@@ -633,6 +634,7 @@ func (wth *workflowTaskHandlerImpl) createWorkflowContext(task *s.PollForDecisio
 	}
 
 	wfStartTime := time.Unix(0, h.Events[0].GetTimestamp())
+	workflowInfo.WorkflowStartTime = wfStartTime
 	return newWorkflowExecutionContext(wfStartTime, workflowInfo, wth), nil
 }
 
@@ -811,6 +813,10 @@ func (w *workflowExecutionContextImpl) ProcessWorkflowTask(workflowTask *workflo
 		return nil, err
 	}
 	w.SetCurrentTask(task)
+	w.workflowInfo.taskListKind = TaskListKindNormal
+	if workflowTask.taskListKind == s.TaskListKindSticky {
+		w.workflowInfo.taskListKind = TaskListKindSticky
+	}
 
 	eventHandler := w.getEventHandler()
 	reorderedHistory := newHistory(workflowTask, eventHandler)
@@ -88,6 +88,18 @@ func registerWorkflows(r *registry) {
 		binaryChecksumWorkflowFunc,
 		RegisterWorkflowOptions{Name: "BinaryChecksumWorkflow"},
 	)
+	r.RegisterWorkflowWithOptions(
+		taskListKindWorkflowFunc,
+		RegisterWorkflowOptions{Name: "TaskListKindWorkflow"},
+	)
+	r.RegisterWorkflowWithOptions(
+		firstDecisionWorkflowFunc,
+		RegisterWorkflowOptions{Name: "FirstDecisionWorkflow"},
+	)
+	r.RegisterWorkflowWithOptions(
+		historyLengthWorkflowFunc,
+		RegisterWorkflowOptions{Name: "HistoryLengthWorkflow"},
+	)
 }
 
 func returnPanicWorkflowFunc(ctx Context, input []byte) error {
@@ -98,6 +110,26 @@ func panicWorkflowFunc(ctx Context, input []byte) error {
 	panic("panicError")
 }
 
+func taskListKindWorkflowFunc(ctx Context, input []byte) (bool, error) {
+	return GetCurrentTaskListKind(ctx) == TaskListKindSticky, nil
+}
+
+func firstDecisionWorkflowFunc(ctx Context) ([]bool, error) {
+	var result []bool
+	result = append(result, IsFirstDecision(ctx))
+	Sleep(ctx, time.Hour)
+	result = append(result, IsFirstDecision(ctx))
+	return result, nil
+}
+
+func historyLengthWorkflowFunc(ctx Context) ([]int64, error) {
+	var result []int64
+	result = append(result, getWorkflowEnvironment(ctx).GetHistoryLength())
+	Sleep(ctx, time.Hour)
+	result = append(result, getWorkflowEnvironment(ctx).GetHistoryLength())
+	return result, nil
+}
+
 func getWorkflowInfoWorkflowFunc(ctx Context, expectedLastCompletionResult string) (info *WorkflowInfo, err error) {
 	result := GetWorkflowInfo(ctx)
 	var lastCompletionResult string
@@ -367,6 +399,93 @@ func (t *TaskHandlersTestSuite) TestWorkflowTask_BinaryChecksum() {
 	t.Equal(getBinaryChecksum(), checksums[2])
 }
 
+func (t *TaskHandlersTestSuite) TestWorkflowTask_TaskListKind() {
+	taskList := "tl1"
+	testEvents := []*s.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &s.WorkflowExecutionStartedEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskScheduled(2, &s.DecisionTaskScheduledEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskStarted(3),
+	}
+	params := workerExecutionParameters{
+		TaskList: taskList,
+		Identity: "test-id-1",
+		Logger:   t.logger,
+	}
+	taskHandler := newWorkflowTaskHandler(testDomain, params, nil, t.registry)
+
+	for _, kind := range []s.TaskListKind{s.TaskListKindNormal, s.TaskListKindSticky} {
+		task := createWorkflowTask(testEvents, 0, "TaskListKindWorkflow")
+		request, err := taskHandler.ProcessWorkflowTask(&workflowTask{task: task, taskListKind: kind}, nil)
+		t.NoError(err)
+		response := request.(*s.RespondDecisionTaskCompletedRequest)
+		t.Equal(1, len(response.Decisions))
+		t.Equal(s.DecisionTypeCompleteWorkflowExecution, response.Decisions[0].GetDecisionType())
+		var isSticky bool
+		t.NoError(json.Unmarshal(response.Decisions[0].CompleteWorkflowExecutionDecisionAttributes.Result, &isSticky))
+		t.Equal(kind == s.TaskListKindSticky, isSticky)
+	}
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_FirstDecision() {
+	taskList := "tl1"
+	testEvents := []*s.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &s.WorkflowExecutionStartedEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskScheduled(2, &s.DecisionTaskScheduledEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskStarted(3),
+		createTestEventDecisionTaskCompleted(4, &s.DecisionTaskCompletedEventAttributes{ScheduledEventId: common.Int64Ptr(2)}),
+		createTestEventTimerStarted(5, 0),
+		createTestEventTimerFired(6, 0),
+		createTestEventDecisionTaskScheduled(7, &s.DecisionTaskScheduledEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskStarted(8),
+	}
+	// the first decision is replayed, IsFirstDecision must report the same value as in the original execution
+	task := createWorkflowTask(testEvents, 3, "FirstDecisionWorkflow")
+	params := workerExecutionParameters{
+		TaskList: taskList,
+		Identity: "test-id-1",
+		Logger:   t.logger,
+	}
+	taskHandler := newWorkflowTaskHandler(testDomain, params, nil, t.registry)
+	request, err := taskHandler.ProcessWorkflowTask(&workflowTask{task: task}, nil)
+	t.NoError(err)
+	response := request.(*s.RespondDecisionTaskCompletedRequest)
+	t.Equal(1, len(response.Decisions))
+	t.Equal(s.DecisionTypeCompleteWorkflowExecution, response.Decisions[0].GetDecisionType())
+	var result []bool
+	t.NoError(json.Unmarshal(response.Decisions[0].CompleteWorkflowExecutionDecisionAttributes.Result, &result))
+	t.Equal([]bool{true, false}, result)
+}
+
+func (t *TaskHandlersTestSuite) TestWorkflowTask_HistoryLength() {
+	taskList := "tl1"
+	testEvents := []*s.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &s.WorkflowExecutionStartedEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskScheduled(2, &s.DecisionTaskScheduledEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskStarted(3),
+		createTestEventDecisionTaskCompleted(4, &s.DecisionTaskCompletedEventAttributes{ScheduledEventId: common.Int64Ptr(2)}),
+		createTestEventTimerStarted(5, 0),
+		createTestEventTimerFired(6, 0),
+		createTestEventDecisionTaskScheduled(7, &s.DecisionTaskScheduledEventAttributes{TaskList: &s.TaskList{Name: &taskList}}),
+		createTestEventDecisionTaskStarted(8),
+	}
+	// the history length is the ID of the decision task started event, so it is the same on replay
+	task := createWorkflowTask(testEvents, 3, "HistoryLengthWorkflow")
+	params := workerExecutionParameters{
+		TaskList: taskList,
+		Identity: "test-id-1",
+		Logger:   t.logger,
+	}
+	taskHandler := newWorkflowTaskHandler(testDomain, params, nil, t.registry)
+	request, err := taskHandler.ProcessWorkflowTask(&workflowTask{task: task}, nil)
+	t.NoError(err)
+	response := request.(*s.RespondDecisionTaskCompletedRequest)
+	t.Equal(1, len(response.Decisions))
+	t.Equal(s.DecisionTypeCompleteWorkflowExecution, response.Decisions[0].GetDecisionType())
+	var result []int64
+	t.NoError(json.Unmarshal(response.Decisions[0].CompleteWorkflowExecutionDecisionAttributes.Result, &result))
+	t.Equal([]int64{3, 8}, result)
+}
+
 func (t *TaskHandlersTestSuite) TestWorkflowTask_ActivityTaskScheduled() {
 	// Schedule an activity and see if we complete workflow.
 	taskList := "tl1"
@@ -776,6 +776,7 @@ func (wtp *workflowTaskPoller) poll(ctx context.Context) (interface{}, error) {
 	wtp.updateBacklog(request.TaskList.GetKind(), response.GetBacklogCountHint())
 
 	task := wtp.toWorkflowTask(response)
+	task.taskListKind = request.TaskList.GetKind()
 	traceLog(func() {
 		var firstEventID int64 = -1
 		if response.History != nil && len(response.History.Events) > 0 {
@@ -993,6 +993,9 @@ func newAggregatedWorker(
 		Tracer:                               wOptions.Tracer,
 		WorkflowInterceptors:                 wOptions.WorkflowInterceptorChainFactories,
 	}
+	if workerParams.DataConverter == nil {
+		workerParams.DataConverter = getDomainDataConverter(domain)
+	}
 
 	ensureRequiredParams(&workerParams)
 	workerParams.MetricsScope = tagScope(workerParams.MetricsScope, tagDomain, domain, tagTaskList, taskList, clientImplHeaderName, clientImplHeaderValue)
@@ -72,6 +72,7 @@ type (
 		workflowTimerClient
 		SideEffect(f func() ([]byte, error), callback resultHandler)
 		GetVersion(changeID string, minSupported, maxSupported Version) Version
+		GetVersions() map[string]Version
 		WorkflowInfo() *WorkflowInfo
 		Complete(result []byte, err error)
 		RegisterCancelHandler(handler func())
@@ -84,6 +85,8 @@ type (
 		SignalExternalWorkflow(domainName, workflowID, runID, signalName string, input []byte, arg interface{}, childWorkflowOnly bool, callback resultHandler)
 		RegisterQueryHandler(handler func(queryType string, queryArgs []byte) ([]byte, error))
 		IsReplaying() bool
+		IsFirstDecision() bool
+		GetHistoryLength() int64
 		MutableSideEffect(id string, f func() interface{}, equals func(a, b interface{}) bool) Value
 		GetDataConverter() DataConverter
 		AddSession(sessionInfo *SessionInfo)
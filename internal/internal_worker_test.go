@@ -166,6 +166,22 @@ func testReplayWorkflow(ctx Context) error {
 	return err
 }
 
+func testReplayOverrideWorkflow(ctx Context) (string, error) {
+	ao := ActivityOptions{
+		ScheduleToStartTimeout: time.Second,
+		StartToCloseTimeout:    time.Second,
+	}
+	ctx = WithActivityOptions(ctx, ao)
+	var result string
+	if err := ExecuteActivity(ctx, "testActivity").Get(ctx, &result); err != nil {
+		return "", err
+	}
+	if result == "approved" {
+		return "approved", nil
+	}
+	return "rejected", nil
+}
+
 func testReplayWorkflowLocalActivity(ctx Context) error {
 	ao := LocalActivityOptions{
 		ScheduleToCloseTimeout: time.Second,
@@ -260,6 +276,74 @@ func (s *internalWorkerTestSuite) TestReplayWorkflowHistory() {
 	require.NoError(s.T(), err)
 }
 
+// testReplayOverrideHistory returns the history of a testReplayOverrideWorkflow run whose activity returned
+// activityResult and that completed with workflowResult.
+func (s *internalWorkerTestSuite) testReplayOverrideHistory(activityResult, workflowResult string) *shared.History {
+	taskList := "taskList1"
+	recordedActivityResult, err := encodeArg(nil, activityResult)
+	s.NoError(err)
+	recordedWorkflowResult, err := encodeArg(nil, workflowResult)
+	s.NoError(err)
+	testEvents := []*shared.HistoryEvent{
+		createTestEventWorkflowExecutionStarted(1, &shared.WorkflowExecutionStartedEventAttributes{
+			WorkflowType: &shared.WorkflowType{Name: common.StringPtr("go.uber.org/cadence/internal.testReplayOverrideWorkflow")},
+			TaskList:     &shared.TaskList{Name: common.StringPtr(taskList)},
+			Input:        testEncodeFunctionArgs(getDefaultDataConverter()),
+		}),
+		createTestEventDecisionTaskScheduled(2, &shared.DecisionTaskScheduledEventAttributes{}),
+		createTestEventDecisionTaskStarted(3),
+		createTestEventDecisionTaskCompleted(4, &shared.DecisionTaskCompletedEventAttributes{}),
+		createTestEventActivityTaskScheduled(5, &shared.ActivityTaskScheduledEventAttributes{
+			ActivityId:   common.StringPtr("0"),
+			ActivityType: &shared.ActivityType{Name: common.StringPtr("testActivity")},
+			TaskList:     &shared.TaskList{Name: &taskList},
+		}),
+		createTestEventActivityTaskStarted(6, &shared.ActivityTaskStartedEventAttributes{
+			ScheduledEventId: common.Int64Ptr(5),
+		}),
+		createTestEventActivityTaskCompleted(7, &shared.ActivityTaskCompletedEventAttributes{
+			ScheduledEventId: common.Int64Ptr(5),
+			StartedEventId:   common.Int64Ptr(6),
+			Result:           recordedActivityResult,
+		}),
+		createTestEventDecisionTaskScheduled(8, &shared.DecisionTaskScheduledEventAttributes{}),
+		createTestEventDecisionTaskStarted(9),
+		createTestEventDecisionTaskCompleted(10, &shared.DecisionTaskCompletedEventAttributes{
+			ScheduledEventId: common.Int64Ptr(8),
+			StartedEventId:   common.Int64Ptr(9),
+		}),
+		createTestEventWorkflowExecutionCompleted(11, &shared.WorkflowExecutionCompletedEventAttributes{
+			DecisionTaskCompletedEventId: common.Int64Ptr(10),
+			Result:                       recordedWorkflowResult,
+		}),
+	}
+	return &shared.History{Events: testEvents}
+}
+
+func (s *internalWorkerTestSuite) TestReplayWorkflowHistory_OverrideActivityResult() {
+	// the replayer fails when the workflow result differs from the recorded one, so replaying a history that
+	// completed with the branch the workflow is expected to take asserts the branch
+	approved := s.testReplayOverrideHistory("approved", "approved")
+	rejected := s.testReplayOverrideHistory("approved", "rejected")
+	recorded := approved.Events[6].ActivityTaskCompletedEventAttributes.Result
+	logger := getLogger()
+
+	replayer := NewWorkflowReplayer()
+	replayer.RegisterWorkflow(testReplayOverrideWorkflow)
+	s.NoError(replayer.ReplayWorkflowHistory(logger, approved))
+	s.Error(replayer.ReplayWorkflowHistory(logger, rejected))
+
+	s.NoError(replayer.OverrideActivityResult("testActivity", "denied"))
+	s.NoError(replayer.ReplayWorkflowHistory(logger, rejected))
+	s.Error(replayer.ReplayWorkflowHistory(logger, approved))
+	s.Equal(recorded, approved.Events[6].ActivityTaskCompletedEventAttributes.Result, "the history must not be modified")
+
+	// overrides by ID take precedence
+	s.NoError(replayer.OverrideActivityResultByID("0", "approved"))
+	s.NoError(replayer.ReplayWorkflowHistory(logger, approved))
+	s.Error(replayer.ReplayWorkflowHistory(logger, rejected))
+}
+
 func (s *internalWorkerTestSuite) TestReplayWorkflowHistory_Incomplete() {
 	taskList := "taskList1"
 	testEvents := []*shared.HistoryEvent{
@@ -78,6 +78,11 @@ type (
 		settable Settable // used to unblock the future when all coroutines have completed
 	}
 
+	// Implements Mutex interface
+	mutexImpl struct {
+		channel Channel // holds a single value while the mutex is locked
+	}
+
 	// Dispatcher is a container of a set of coroutines.
 	dispatcher interface {
 		// ExecuteUntilAllBlocked executes coroutines one by one in deterministic order
@@ -112,8 +117,8 @@ type (
 		name            string             // human readable channel name
 		size            int                // Channel buffer size. 0 for non buffered.
 		buffer          []interface{}      // buffered messages
-		blockedSends    []*sendCallback    // puts waiting when buffer is full.
-		blockedReceives []*receiveCallback // receives waiting when no messages are available.
+		blockedSends    []*sendCallback    // puts waiting when buffer is full, served in FIFO order.
+		blockedReceives []*receiveCallback // receives waiting when no messages are available, served in FIFO order.
 		closed          bool               // true if channel is closed.
 		recValue        *interface{}       // Used only while receiving value, this is used as pre-fetch buffer value from the channel.
 		dataConverter   DataConverter      // for decode data
@@ -136,6 +141,8 @@ type (
 		name        string
 		cases       []*selectCase // cases that this select is comprised from
 		defaultFunc *func()       // default case
+		wakeFunc    *func()       // wake case
+		woken       bool          // Wake was called since the last Select returned
 	}
 
 	// unblockFunc is passed evaluated by a coroutine yield. When it returns false the yield returns to a caller.
@@ -158,6 +165,7 @@ type (
 		sequence         int
 		channelSequence  int // used to name channels
 		selectorSequence int // used to name channels
+		randSequence     int // used to seed the sources of NewRand
 		coroutines       []*coroutineState
 		executing        bool       // currently running ExecuteUntilAllBlocked. Used to avoid recursive calls to it.
 		mutex            sync.Mutex // used to synchronize executing
@@ -174,6 +182,8 @@ type (
 		workflowID                          string
 		waitForCancellation                 bool
 		signalChannels                      map[string]Channel
+		signalChannelOptions                map[string]SignalChannelOptions
+		droppedSignals                      map[string]int
 		queryHandlers                       map[string]func([]byte) ([]byte, error)
 		workflowIDReusePolicy               WorkflowIDReusePolicy
 		dataConverter                       DataConverter
@@ -183,6 +193,7 @@ type (
 		memo                                map[string]interface{}
 		searchAttributes                    map[string]interface{}
 		parentClosePolicy                   ParentClosePolicy
+		activityLimit                       int
 	}
 
 	executeWorkflowParams struct {
@@ -198,7 +209,8 @@ type (
 	// decodeFutureImpl
 	decodeFutureImpl struct {
 		*futureImpl
-		fn interface{}
+		fn            interface{}
+		dataConverter DataConverter // overrides the data converter of the context passed to Get when not nil
 	}
 
 	childWorkflowFutureImpl struct {
@@ -247,6 +259,7 @@ const (
 var _ Channel = (*channelImpl)(nil)
 var _ Selector = (*selectorImpl)(nil)
 var _ WaitGroup = (*waitGroupImpl)(nil)
+var _ Mutex = (*mutexImpl)(nil)
 var _ dispatcher = (*dispatcherImpl)(nil)
 
 var stackBuf [100000]byte
@@ -280,6 +293,7 @@ type workflowEnvironmentInterceptor struct {
 	env                  workflowEnvironment
 	interceptorChainHead WorkflowInterceptor
 	fn                   interface{}
+	scheduledActivities  int // number of activities scheduled by the workflow run, see WithActivityLimit
 }
 
 func getWorkflowInterceptor(ctx Context) WorkflowInterceptor {
@@ -346,6 +360,31 @@ func (f *futureImpl) IsReady() bool {
 	return f.ready
 }
 
+func (f *futureImpl) GetWithTimeout(ctx Context, d time.Duration) (interface{}, error, bool) {
+	if !f.ready {
+		timerCtx, cancelTimer := WithCancel(ctx)
+		defer cancelTimer() // cancel the timer if the future wins the race
+		timer := NewTimer(timerCtx, d)
+		var timerErr error
+		timedOut := false
+		NewSelector(ctx).
+			AddFuture(f, func(Future) {}).
+			AddFuture(timer, func(t Future) {
+				timerErr = t.Get(ctx, nil)
+				timedOut = true
+			}).
+			Select(ctx)
+		if timedOut {
+			if timerErr != nil {
+				// timer was canceled together with ctx
+				return nil, timerErr, false
+			}
+			return nil, nil, true
+		}
+	}
+	return f.value, f.err, false
+}
+
 func (f *futureImpl) Set(value interface{}, err error) {
 	if f.ready {
 		panic("already set")
@@ -373,6 +412,13 @@ func (f *futureImpl) SetError(err error) {
 	f.Set(nil, err)
 }
 
+func (f *futureImpl) SetCancel() {
+	if f.ready {
+		panic("already set")
+	}
+	f.Set(nil, ErrCanceled)
+}
+
 func (f *futureImpl) Chain(future Future) {
 	if f.ready {
 		panic("already set")
@@ -387,10 +433,35 @@ func (f *futureImpl) Chain(future Future) {
 		return
 	}
 	val, err := ch.GetValueAndError()
-	f.value = val
-	f.err = err
-	f.ready = true
-	return
+	f.Set(val, err)
+}
+
+func (f *futureImpl) ChainWithTransform(future Future, transform func(v interface{}) (interface{}, error)) {
+	if f.ready {
+		panic("already set")
+	}
+
+	ch, ok := future.(asyncFuture)
+	if !ok {
+		panic("cannot chain Future that wasn't created with workflow.NewFuture")
+	}
+	setTransformed := func() {
+		val, err := ch.GetValueAndError()
+		if err == nil {
+			if val, err = transform(val); err != nil {
+				val = nil
+			}
+		}
+		f.Set(val, err)
+	}
+	if ch.IsReady() {
+		setTransformed()
+		return
+	}
+	ch.GetAsync(&receiveCallback{fn: func(v interface{}, more bool) bool {
+		setTransformed()
+		return false
+	}})
 }
 
 func (f *futureImpl) ChainFuture(future Future) {
@@ -487,8 +558,20 @@ func (d *syncWorkflowDefinition) Execute(env workflowEnvironment, header *shared
 
 	getWorkflowEnvironment(d.rootCtx).RegisterSignalHandler(func(name string, result []byte) {
 		eo := getWorkflowEnvOptions(d.rootCtx)
+		if opts, ok := eo.signalChannelOptions[name]; ok && opts.MaxPayloadSize > 0 && len(result) > opts.MaxPayloadSize {
+			// Oversize signal is not delivered, so the workflow never tries to decode it from the channel.
+			if opts.OversizeSignalHandler != nil {
+				opts.OversizeSignalHandler(name, result)
+			}
+			return
+		}
 		// We don't want this code to be blocked ever, using sendAsync().
 		ch := eo.getSignalChannel(d.rootCtx, name).(*channelImpl)
+		if opts, ok := eo.signalChannelOptions[name]; ok && opts.MaxBufferedSignals > 0 && ch.Len() >= opts.MaxBufferedSignals {
+			// Only the count of the signals over the limit is kept, see GetDroppedSignalCount.
+			eo.droppedSignals[name]++
+			return
+		}
 		ok := ch.SendAsync(result)
 		if !ok {
 			panic(fmt.Sprintf("Exceeded channel buffer size for signal: %v", name))
@@ -644,6 +727,17 @@ func (c *channelImpl) ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, m
 	}
 }
 
+func (c *channelImpl) ReceiveAll(ctx Context) []interface{} {
+	values := []interface{}{}
+	for {
+		v, ok, _ := c.receiveAsyncImpl(nil)
+		if !ok {
+			return values
+		}
+		values = append(values, v)
+	}
+}
+
 // ok = true means that value was received
 // more = true means that channel is not closed and more deliveries are possible
 func (c *channelImpl) receiveAsyncImpl(callback *receiveCallback) (v interface{}, ok bool, more bool) {
@@ -734,6 +828,45 @@ func (c *channelImpl) Send(ctx Context, v interface{}) {
 	}
 }
 
+func (c *channelImpl) SendWithTimeout(ctx Context, v interface{}, d time.Duration) (sent bool) {
+	state := getState(ctx)
+	valueConsumed := false
+	callback := &sendCallback{
+		value: v,
+		fn: func() bool {
+			valueConsumed = true
+			return true
+		},
+	}
+	ok := c.sendAsyncImpl(v, callback)
+	if ok {
+		state.unblocked()
+		return true
+	}
+
+	timerCtx, cancelTimer := WithCancel(ctx)
+	defer cancelTimer()
+	timer := NewTimer(timerCtx, d)
+	for {
+		if valueConsumed {
+			state.unblocked()
+			return true
+		}
+
+		// Check for closed in the loop as close can be called when send is blocked
+		if c.closed {
+			panic("Closed channel")
+		}
+		if timer.IsReady() {
+			// the value must not be delivered after we gave up on it
+			c.removeSendCallback(callback)
+			state.unblocked()
+			return false
+		}
+		state.yield(fmt.Sprintf("blocked on %s.SendWithTimeout", c.name))
+	}
+}
+
 func (c *channelImpl) SendAsync(v interface{}) (ok bool) {
 	return c.sendAsyncImpl(v, nil)
 }
@@ -762,6 +895,13 @@ func (c *channelImpl) sendAsyncImpl(v interface{}, pair *sendCallback) (ok bool)
 }
 
 func (c *channelImpl) Close() {
+	c.CloseOnce()
+}
+
+func (c *channelImpl) CloseOnce() bool {
+	if c.closed {
+		return false
+	}
 	c.closed = true
 	// Use a copy of blockedReceives for iteration as invoking callback could result in modification
 	copy := append(c.blockedReceives[:0:0], c.blockedReceives...)
@@ -769,6 +909,26 @@ func (c *channelImpl) Close() {
 		callback.fn(nil, false)
 	}
 	// All blocked sends are going to panic
+	return true
+}
+
+// canReceive returns true if a receive from the channel would not block.
+func (c *channelImpl) canReceive() bool {
+	return c.recValue != nil || len(c.buffer) > 0 || c.closed || len(c.blockedSends) > 0
+}
+
+// canSend returns true if a send to the channel would not block.
+func (c *channelImpl) canSend() bool {
+	return !c.closed && (len(c.blockedReceives) > 0 || len(c.buffer) < c.size)
+}
+
+func (c *channelImpl) Len() int {
+	n := len(c.buffer)
+	if c.recValue != nil {
+		// value pre-fetched by a Selector is still up for delivery
+		n++
+	}
+	return n
 }
 
 // Takes a value and assigns that 'to' value. logs a metric if it is unable to deserialize
@@ -966,6 +1126,12 @@ func (d *dispatcherImpl) Close() {
 }
 
 func (d *dispatcherImpl) StackTrace() string {
+	return d.stackTrace(nil)
+}
+
+// stackTrace returns the stack traces of the coroutines. running is the coroutine that calls it, if any, its stack
+// trace is captured directly as it is not blocked.
+func (d *dispatcherImpl) stackTrace(running *coroutineState) string {
 	var result string
 	for i := 0; i < len(d.coroutines); i++ {
 		c := d.coroutines[i]
@@ -973,8 +1139,30 @@ func (d *dispatcherImpl) StackTrace() string {
 			if len(result) > 0 {
 				result += "\n\n"
 			}
-			result += c.stackTrace()
+			if c == running {
+				// omit the frames up to GetStackTrace
+				result += getStackTrace(c.name, "running", 4)
+			} else {
+				result += c.stackTrace()
+			}
+		}
+	}
+	return result
+}
+
+// snapshot describes the coroutines that are still running as blocked since now, it must be called once all of them
+// are blocked.
+func (d *dispatcherImpl) snapshot(now time.Time) DispatcherSnapshot {
+	result := DispatcherSnapshot{Now: now}
+	for _, c := range d.coroutines {
+		if c.closed {
+			continue
 		}
+		result.Coroutines = append(result.Coroutines, CoroutineSnapshot{
+			Name:         c.name,
+			BlockedSince: now,
+			StackTrace:   c.stackTrace(),
+		})
 	}
 	return result
 }
@@ -998,21 +1186,106 @@ func (s *selectorImpl) AddFuture(future Future, f func(future Future)) Selector
 	return s
 }
 
-func (s *selectorImpl) AddDefault(f func()) {
+func (s *selectorImpl) AddDefault(f func()) Selector {
 	s.defaultFunc = &f
+	return s
+}
+
+func (s *selectorImpl) Wake() {
+	s.woken = true
+}
+
+func (s *selectorImpl) AddWake(f func()) Selector {
+	s.wakeFunc = &f
+	return s
+}
+
+func (s *selectorImpl) HasDefault() bool {
+	return s.defaultFunc != nil
+}
+
+func (s *selectorImpl) SelectWithDefault(ctx Context, f func()) {
+	s.selectWithDefault(f, func() { s.Select(ctx) })
+}
+
+// selectWithDefault calls doSelect with f as the default case, and restores the default case set by AddDefault after.
+func (s *selectorImpl) selectWithDefault(f func(), doSelect func()) {
+	defaultFunc := s.defaultFunc
+	defer func() { s.defaultFunc = defaultFunc }()
+	s.defaultFunc = &f
+	doSelect()
+}
+
+func (s *selectorImpl) RemoveReceive(c Channel) Selector {
+	ch, ok := c.(*channelImpl)
+	if !ok {
+		return s
+	}
+	s.removeCases(func(pair *selectCase) bool {
+		return pair.receiveFunc != nil && pair.channel == ch
+	})
+	return s
+}
+
+func (s *selectorImpl) RemoveFuture(future Future) Selector {
+	asyncF, ok := future.(asyncFuture)
+	if !ok {
+		return s
+	}
+	s.removeCases(func(pair *selectCase) bool {
+		return pair.future != nil && pair.future == asyncF
+	})
+	return s
+}
+
+func (s *selectorImpl) HasPending() bool {
+	for _, pair := range s.cases {
+		if pair.receiveFunc != nil && pair.channel.canReceive() {
+			return true
+		}
+		if pair.sendFunc != nil && pair.channel.canSend() {
+			return true
+		}
+		if pair.futureFunc != nil && pair.future.IsReady() {
+			return true
+		}
+	}
+	return false
+}
+
+// removeCases drops every case for which match returns true, preserving the order of the remaining ones.
+func (s *selectorImpl) removeCases(match func(pair *selectCase) bool) {
+	cases := s.cases[:0]
+	for _, pair := range s.cases {
+		if !match(pair) {
+			cases = append(cases, pair)
+		}
+	}
+	for i := len(cases); i < len(s.cases); i++ {
+		s.cases[i] = nil
+	}
+	s.cases = cases
 }
 
 func (s *selectorImpl) Select(ctx Context) {
+	s.SelectIndex(ctx)
+}
+
+func (s *selectorImpl) SelectIndex(ctx Context) int {
 	state := getState(ctx)
 	var readyBranch func()
+	readyIndex := -1
 	var cleanups []func()
 	defer func() {
 		for _, c := range cleanups {
 			c()
 		}
+		// a pending Wake is consumed by this Select() call, whichever case it executed.
+		s.woken = false
 	}()
 
-	for _, pair := range s.cases {
+	for i, pair := range s.cases {
+		i := i
 		if pair.receiveFunc != nil {
 			f := *pair.receiveFunc
 			c := pair.channel
@@ -1022,9 +1295,13 @@ func (s *selectorImpl) Select(ctx Context) {
 						return false
 					}
 					readyBranch = func() {
-						c.recValue = &v
+						// a closed channel has no value to deliver, see below
+						if more {
+							c.recValue = &v
+						}
 						f(c, more)
 					}
+					readyIndex = i
 					return true
 				},
 			}
@@ -1041,7 +1318,7 @@ func (s *selectorImpl) Select(ctx Context) {
 					c.recValue = &v
 				}
 				f(c, more)
-				return
+				return i
 			}
 			// callback closure is added to channel's blockedReceives, we need to clean it up to avoid closure leak
 			cleanups = append(cleanups, func() {
@@ -1059,6 +1336,7 @@ func (s *selectorImpl) Select(ctx Context) {
 					readyBranch = func() {
 						f()
 					}
+					readyIndex = i
 					return true
 				},
 			}
@@ -1070,7 +1348,7 @@ func (s *selectorImpl) Select(ctx Context) {
 				readyBranch = func() {
 				}
 				f()
-				return
+				return i
 			}
 			// callback closure is added to channel's blockedSends, we need to clean it up to avoid closure leak
 			cleanups = append(cleanups, func() {
@@ -1088,6 +1366,7 @@ func (s *selectorImpl) Select(ctx Context) {
 						p.futureFunc = nil
 						f(p.future)
 					}
+					readyIndex = i
 					return true
 				},
 			}
@@ -1101,7 +1380,7 @@ func (s *selectorImpl) Select(ctx Context) {
 				}
 				p.futureFunc = nil
 				f(p.future)
-				return
+				return i
 			}
 			// callback closure is added to future's channel's blockedReceives, need to clean up to avoid leak
 			cleanups = append(cleanups, func() {
@@ -1112,13 +1391,23 @@ func (s *selectorImpl) Select(ctx Context) {
 	if s.defaultFunc != nil {
 		f := *s.defaultFunc
 		f()
-		return
+		return -1
 	}
 	for {
 		if readyBranch != nil {
 			readyBranch()
 			state.unblocked()
-			return
+			return readyIndex
+		}
+		if s.woken {
+			// prevent the callbacks of the cases from consuming values for this Select() call.
+			readyBranch = func() {}
+			if s.wakeFunc != nil {
+				f := *s.wakeFunc
+				f()
+			}
+			state.unblocked()
+			return -1
 		}
 		state.yield(fmt.Sprintf("blocked on %s.Select", s.name))
 	}
@@ -1216,6 +1505,8 @@ func setWorkflowEnvOptionsIfNotExist(ctx Context) Context {
 		newOptions = *options
 	} else {
 		newOptions.signalChannels = make(map[string]Channel)
+		newOptions.signalChannelOptions = make(map[string]SignalChannelOptions)
+		newOptions.droppedSignals = make(map[string]int)
 		newOptions.queryHandlers = make(map[string]func([]byte) ([]byte, error))
 	}
 	if newOptions.dataConverter == nil {
@@ -1293,7 +1584,11 @@ func (d *decodeFutureImpl) Get(ctx Context, value interface{}) error {
 		return errors.New("value parameter is not a pointer")
 	}
 
-	err := deSerializeFunctionResult(d.fn, d.futureImpl.value.([]byte), value, getDataConverterFromWorkflowContext(ctx), d.channel.env.GetRegistry())
+	dc := d.dataConverter
+	if dc == nil {
+		dc = getDataConverterFromWorkflowContext(ctx)
+	}
+	err := deSerializeFunctionResult(d.fn, d.futureImpl.value.([]byte), value, dc, d.channel.env.GetRegistry())
 	if err != nil {
 		return err
 	}
@@ -1304,7 +1599,9 @@ func (d *decodeFutureImpl) Get(ctx Context, value interface{}) error {
 // fn - the decoded value needs to be validated against a function.
 func newDecodeFuture(ctx Context, fn interface{}) (Future, Settable) {
 	impl := &decodeFutureImpl{
-		&futureImpl{channel: NewChannel(ctx).(*channelImpl)}, fn}
+		futureImpl: &futureImpl{channel: NewChannel(ctx).(*channelImpl)},
+		fn:         fn,
+	}
 	return impl, impl
 }
 
@@ -1449,3 +1746,17 @@ func (wg *waitGroupImpl) Wait(ctx Context) {
 	}
 	wg.future, wg.settable = NewFuture(ctx)
 }
+
+// Lock locks the mutex. If the mutex is already locked, the calling
+// coroutine blocks until the mutex is available.
+func (m *mutexImpl) Lock(ctx Context) {
+	m.channel.Send(ctx, struct{}{})
+}
+
+// Unlock unlocks the mutex, unblocking one of the coroutines waiting in Lock if any.
+// It panics if the mutex is not locked.
+func (m *mutexImpl) Unlock() {
+	if !m.channel.ReceiveAsync(nil) {
+		panic("unlock of unlocked Mutex")
+	}
+}
@@ -1232,6 +1232,23 @@ func (s *workflowClientTestSuite) TestSerializeSearchAttributes() {
 	s.Error(err)
 }
 
+func (s *workflowClientTestSuite) TestCompleteActivityWithService() {
+	taskToken := []byte("task-token")
+	var request *shared.RespondActivityTaskCompletedRequest
+	s.service.EXPECT().RespondActivityTaskCompleted(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(_ interface{}, req *shared.RespondActivityTaskCompletedRequest, _ ...interface{}) {
+			request = req
+		}).Return(nil)
+
+	s.NoError(CompleteActivity(context.Background(), s.service, taskToken, "result", nil))
+	s.Equal(taskToken, request.TaskToken)
+	var result string
+	s.NoError(getDefaultDataConverter().FromData(request.Result, &result))
+	s.Equal("result", result)
+
+	s.Error(CompleteActivity(context.Background(), s.service, nil, "result", nil))
+}
+
 func (s *workflowClientTestSuite) TestListWorkflow() {
 	request := &shared.ListWorkflowExecutionsRequest{}
 	response := &shared.ListWorkflowExecutionsResponse{}
@@ -22,6 +22,7 @@ package internal
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -764,6 +765,310 @@ func (s *WorkflowUnitTest) Test_CorruptedSignalOnClosedChannelWorkflow_Receive_S
 	s.Equal("false", result[0].Value)
 }
 
+func oversizeSignalWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	ch := GetSignalChannelWithOptions(ctx, "guardedSignal", SignalChannelOptions{
+		MaxPayloadSize: 16,
+		OversizeSignalHandler: func(signalName string, payload []byte) {
+			result = append(result, fmt.Sprintf("oversize-%v-%v", signalName, len(payload)))
+		},
+	})
+	var v string
+	ch.Receive(ctx, &v)
+	result = append(result, v)
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_OversizeSignalWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+
+	// Setup signals.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("guardedSignal", strings.Repeat("a", 32))
+	}, time.Second)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("guardedSignal", "small")
+	}, 2*time.Second)
+
+	env.ExecuteWorkflow(oversizeSignalWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	// json encoding adds quotes and a trailing new line to the payload
+	s.EqualValues([]string{"oversize-guardedSignal-35", "small"}, result)
+}
+
+func boundedSignalWorkflowTest(ctx Context) ([]string, error) {
+	ch := GetSignalChannelWithOptions(ctx, "floodSignal", SignalChannelOptions{MaxBufferedSignals: 3})
+	// Let the flood arrive before anything is received.
+	if err := Sleep(ctx, time.Minute); err != nil {
+		return nil, err
+	}
+	var result []string
+	var v string
+	for ch.ReceiveAsync(&v) {
+		result = append(result, v)
+	}
+	result = append(result, fmt.Sprintf("%v signals dropped", GetDroppedSignalCount(ctx, "floodSignal")))
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_BoundedSignalWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		for i := 0; i < 10; i++ {
+			env.SignalWorkflow("floodSignal", fmt.Sprintf("signal-%v", i))
+		}
+	}, time.Second)
+
+	env.ExecuteWorkflow(boundedSignalWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.EqualValues([]string{"signal-0", "signal-1", "signal-2", "7 signals dropped"}, result)
+}
+
+func (s *WorkflowUnitTest) Test_SortedMapKeys() {
+	type name string
+	for _, m := range []interface{}{
+		map[string]int{"delta": 4, "alpha": 1, "charlie": 3, "bravo": 2},
+		map[name]struct{}{"delta": {}, "alpha": {}, "charlie": {}, "bravo": {}},
+	} {
+		keys, err := SortedMapKeys(m)
+		s.NoError(err)
+		s.Equal([]string{"alpha", "bravo", "charlie", "delta"}, keys)
+	}
+
+	keys, err := SortedMapKeys(map[string]bool{})
+	s.NoError(err)
+	s.Empty(keys)
+
+	for _, m := range []interface{}{nil, "alpha", []string{"alpha"}, map[int]string{1: "alpha"}} {
+		_, err := SortedMapKeys(m)
+		s.Error(err, "%T", m)
+	}
+}
+
+func collectSignalsWorkflowTest(ctx Context, signalNames []string) ([]string, error) {
+	collected := make(map[string][]byte)
+	selector := NewSelector(ctx)
+	for _, name := range signalNames {
+		name := name
+		selector.AddReceive(GetSignalChannel(ctx, name), func(c Channel, more bool) {
+			var payload []byte
+			c.Receive(ctx, &payload)
+			collected[name] = payload
+		})
+	}
+	for len(collected) < len(signalNames) {
+		selector.Select(ctx)
+	}
+
+	var result []string
+	for _, key := range SortedSignalKeys(collected) {
+		result = append(result, fmt.Sprintf("%v=%s", key, collected[key]))
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_SortedSignalKeysWorkflow() {
+	signalNames := []string{"delta", "alpha", "charlie", "echo", "bravo"}
+	for i := 0; i < 10; i++ {
+		env := s.NewTestWorkflowEnvironment()
+		for j, name := range signalNames {
+			name := name
+			env.RegisterDelayedCallback(func() {
+				env.SignalWorkflow(name, []byte("payload-"+name))
+			}, time.Duration(j+1)*time.Second)
+		}
+
+		env.ExecuteWorkflow(collectSignalsWorkflowTest, signalNames)
+		s.True(env.IsWorkflowCompleted())
+		s.NoError(env.GetWorkflowError())
+
+		var result []string
+		s.NoError(env.GetWorkflowResult(&result))
+		s.Equal([]string{
+			"alpha=payload-alpha",
+			"bravo=payload-bravo",
+			"charlie=payload-charlie",
+			"delta=payload-delta",
+			"echo=payload-echo",
+		}, result)
+	}
+}
+
+func selectFutureOrSignalWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	// the signal arrives before the timer fires
+	ready, _, _, signalValue := SelectFutureOrSignal(ctx, NewTimer(ctx, time.Minute), "cancel")
+	var v string
+	if err := signalValue.(Value).Get(&v); err != nil {
+		return nil, err
+	}
+	result = append(result, fmt.Sprintf("ready-%v-%v", ready, v))
+
+	// both the timer and a signal are ready, the timer wins and the signal stays buffered
+	timer := NewTimer(ctx, time.Second)
+	if err := Sleep(ctx, time.Hour); err != nil {
+		return nil, err
+	}
+	ready, fv, ferr, signalValue := SelectFutureOrSignal(ctx, timer, "cancel")
+	result = append(result, fmt.Sprintf("ready-%v-%v-%v-%v", ready, fv, ferr, signalValue))
+	GetSignalChannel(ctx, "cancel").Receive(ctx, &v)
+	result = append(result, "buffered-"+v)
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_SelectFutureOrSignalWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("cancel", "first")
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow("cancel", "second")
+	}, 10*time.Minute)
+
+	env.ExecuteWorkflow(selectFutureOrSignalWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"ready-false-first", "ready-true-<nil>-<nil>-<nil>", "buffered-second"}, result)
+}
+
+func futureGetWithTimeoutWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	f, settable := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		settable.SetValue("late")
+	})
+
+	_, _, timedOut := f.GetWithTimeout(ctx, 10*time.Second)
+	result = append(result, fmt.Sprintf("timedOut-%v", timedOut))
+
+	v, err, timedOut := f.GetWithTimeout(ctx, time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, fmt.Sprintf("%v-timedOut-%v", v, timedOut))
+
+	canceledCtx, cancel := WithCancel(ctx)
+	pending, _ := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Second)
+		cancel()
+	})
+	_, err, timedOut = pending.GetWithTimeout(canceledCtx, time.Hour)
+	result = append(result, fmt.Sprintf("canceled-%v-timedOut-%v", IsCanceledError(err), timedOut))
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_FutureGetWithTimeoutWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(futureGetWithTimeoutWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.EqualValues([]string{"timedOut-true", "late-timedOut-false", "canceled-true-timedOut-false"}, result)
+}
+
+func sendWithTimeoutWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	c := NewBufferedChannel(ctx, 1)
+	result = append(result, fmt.Sprintf("buffered-%v", c.SendWithTimeout(ctx, "one", time.Minute)))
+	result = append(result, fmt.Sprintf("full-%v", c.SendWithTimeout(ctx, "two", time.Minute)))
+
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Second)
+		var v string
+		c.Receive(ctx, &v)
+		result = append(result, "received-"+v)
+	})
+	result = append(result, fmt.Sprintf("consumer-%v", c.SendWithTimeout(ctx, "three", time.Minute)))
+	result = append(result, fmt.Sprintf("drained-%v", Drain(c)))
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_SendWithTimeoutWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(sendWithTimeoutWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"buffered-true", "full-false", "received-one", "consumer-true", "drained-[three]"}, result)
+}
+
+func timerWithOptionsWorkflowTest(ctx Context) ([]string, error) {
+	var events []string
+	options := func(name string) TimerOptions {
+		return TimerOptions{
+			OnFire:   func() { events = append(events, name+"-fired") },
+			OnCancel: func() { events = append(events, name+"-canceled") },
+		}
+	}
+
+	if err := NewTimerWithOptions(ctx, time.Minute, options("t1")).Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	events = append(events, "t1-ready")
+
+	cancelCtx, cancel := WithCancel(ctx)
+	t2 := NewTimerWithOptions(cancelCtx, time.Hour, options("t2"))
+	NewTimerWithOptions(ctx, time.Minute, options("t3")).Get(ctx, nil)
+	cancel()
+	err := t2.Get(ctx, nil)
+	events = append(events, fmt.Sprintf("t2-ready-%v", IsCanceledError(err)))
+
+	NewTimerWithOptions(ctx, 0, options("t4")).Get(ctx, nil)
+	return events, nil
+}
+
+func (s *WorkflowUnitTest) Test_TimerWithOptionsWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(timerWithOptionsWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"t1-fired", "t1-ready", "t3-fired", "t2-canceled", "t2-ready-true", "t4-fired"}, result)
+}
+
+func timerWithDeadlineWorkflowTest(ctx Context) ([]time.Duration, error) {
+	start := Now(ctx)
+	timer, deadline := NewTimerWithDeadline(ctx, time.Hour)
+	Sleep(ctx, time.Minute)
+	remaining := deadline.Sub(Now(ctx))
+	if err := timer.Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	_, immediate := NewTimerWithDeadline(ctx, -time.Minute)
+	return []time.Duration{deadline.Sub(start), remaining, Now(ctx).Sub(deadline), immediate.Sub(Now(ctx))}, nil
+}
+
+func (s *WorkflowUnitTest) Test_TimerWithDeadlineWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(timerWithDeadlineWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var result []time.Duration
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]time.Duration{time.Hour, 59 * time.Minute, 0, 0}, result)
+}
+
 func closeChannelTest(ctx Context) error {
 	ch := NewChannel(ctx)
 	Go(ctx, func(ctx Context) {
@@ -919,6 +1224,739 @@ func (s *WorkflowUnitTest) Test_ActivityOptionsWorkflow() {
 	s.Equal("id1 id2", result)
 }
 
+func onCompleteHookActivity(input string) (string, error) {
+	if input == "fail" {
+		return "", NewCustomError("failed")
+	}
+	return input + "-done", nil
+}
+
+func onCompleteHookWorkflow(ctx Context) ([]string, error) {
+	var hookCalls []string
+	ao := ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		OnComplete: func(result []byte, err error) {
+			var r string
+			if result != nil {
+				if decodeErr := decodeArg(getDataConverterFromWorkflowContext(ctx), result, &r); decodeErr != nil {
+					panic(decodeErr)
+				}
+			}
+			hookCalls = append(hookCalls, fmt.Sprintf("%v-%v", r, err != nil))
+		},
+	}
+	ctx = WithActivityOptions(ctx, ao)
+
+	f := ExecuteActivity(ctx, onCompleteHookActivity, "ok")
+	// the hook is invoked before the future becomes ready
+	var r string
+	if err := f.Get(ctx, &r); err != nil {
+		return nil, err
+	}
+	hookCalls = append(hookCalls, "future-"+r)
+	err := ExecuteActivity(ctx, onCompleteHookActivity, "fail").Get(ctx, nil)
+	hookCalls = append(hookCalls, fmt.Sprintf("future-err-%v", err != nil))
+	return hookCalls, nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivityOnCompleteHookWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.ExecuteWorkflow(onCompleteHookWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"ok-done-false", "future-ok-done", "-true", "future-err-true"}, result)
+}
+
+func activityLimitWorkflowTest(ctx Context, limit int) ([]string, error) {
+	ctx = WithActivityLimit(ctx, limit)
+	ao := ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	}
+	ctx = WithActivityOptions(ctx, ao)
+
+	var result []string
+	for i := 0; i <= limit; i++ {
+		var r string
+		if err := ExecuteActivity(ctx, onCompleteHookActivity, fmt.Sprintf("a%v", i)).Get(ctx, &r); err != nil {
+			result = append(result, err.Error())
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivityLimitWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.ExecuteWorkflow(activityLimitWorkflowTest, 3)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"a0-done", "a1-done", "a2-done", ErrActivityLimitExceeded.Error()}, result)
+}
+
+func (s *WorkflowUnitTest) Test_ActivityParametersValidation() {
+	ao := ActivityOptions{
+		TaskList:               "tl",
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	}
+	withHeartbeat := ao
+	withHeartbeat.HeartbeatTimeout = time.Hour
+	withoutTaskList := ao
+	withoutTaskList.TaskList = ""
+	withoutTimeouts := ao
+	withoutTimeouts.StartToCloseTimeout = 0
+
+	tests := []struct {
+		name   string
+		ctx    Context
+		params ExecuteActivityParameters
+		err    string
+	}{
+		{"valid", Background(), ExecuteActivityParameters{Activity: onCompleteHookActivity, Options: &ao}, ""},
+		{"valid options of context", WithActivityOptions(Background(), ao),
+			ExecuteActivityParameters{Activity: "activity"}, ""},
+		{"missing activity", Background(), ExecuteActivityParameters{Options: &ao},
+			"invalid activity parameters: missing ActivityType"},
+		{"empty activity name", Background(), ExecuteActivityParameters{Activity: "", Options: &ao},
+			"invalid activity parameters: missing ActivityType"},
+		{"missing options", Background(), ExecuteActivityParameters{Activity: "activity"},
+			"invalid activity parameters: missing activity options"},
+		{"missing task list", Background(), ExecuteActivityParameters{Activity: "activity", Options: &withoutTaskList},
+			"invalid activity parameters: missing TaskListName"},
+		{"missing timeouts", Background(), ExecuteActivityParameters{Activity: "activity", Options: &withoutTimeouts},
+			"invalid activity parameters: at least one of ScheduleToCloseTimeout and StartToCloseTimeout must be positive"},
+		{"heartbeat larger than start to close", Background(),
+			ExecuteActivityParameters{Activity: "activity", Options: &withHeartbeat},
+			"invalid activity parameters: HeartbeatTimeout 1h0m0s is larger than StartToCloseTimeout 1m0s"},
+		{"heartbeat of context larger than start to close", WithActivityOptions(Background(), withHeartbeat),
+			ExecuteActivityParameters{Activity: "activity"},
+			"invalid activity parameters: HeartbeatTimeout 1h0m0s is larger than StartToCloseTimeout 1m0s"},
+	}
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.params.Validate(tt.ctx)
+			if tt.err == "" {
+				s.NoError(err)
+				return
+			}
+			s.True(errors.Is(err, ErrInvalidActivityParameters))
+			s.EqualError(err, tt.err)
+		})
+	}
+}
+
+func (s *WorkflowUnitTest) Test_ExecuteActivitiesAsyncInvalidParameters() {
+	workflowFn := func(ctx Context) ([]string, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			ScheduleToStartTimeout: time.Minute,
+			StartToCloseTimeout:    time.Minute,
+			HeartbeatTimeout:       time.Hour,
+		})
+		_, errs := ExecuteActivities(ctx, []ExecuteActivityParameters{{Activity: onCompleteHookActivity}})
+		var result []string
+		for _, err := range errs {
+			result = append(result, err.Error())
+		}
+
+		// ExecuteActivity doesn't validate the parameters, so that existing histories replay
+		var out string
+		err := ExecuteActivity(ctx, onCompleteHookActivity, "ok").Get(ctx, &out)
+		return append(result, out), err
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.RegisterActivity(onCompleteHookActivity)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"invalid activity parameters: HeartbeatTimeout 1h0m0s is larger than StartToCloseTimeout 1m0s", "ok-done"}, result)
+}
+
+// foreignFuture is a Future that was not created by the workflow package, like a mock.
+type foreignFuture struct {
+	err error
+}
+
+func (f foreignFuture) Get(ctx Context, valuePtr interface{}) error { return f.err }
+
+func (f foreignFuture) IsReady() bool { return true }
+
+func (f foreignFuture) GetWithTimeout(ctx Context, d time.Duration) (interface{}, error, bool) {
+	return nil, f.err, false
+}
+
+func (s *WorkflowUnitTest) Test_AwaitAllForeignFuture() {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		f, settable := NewFuture(ctx)
+		settable.SetValue("value")
+		values, errs := AwaitAll(ctx, []Future{f, foreignFuture{}, foreignFuture{err: errors.New("failed")}})
+		s.Equal([]interface{}{"value", nil, nil}, values)
+		s.Equal([]error{nil, nil, errors.New("failed")}, errs)
+	})
+	s.NoError(d.ExecuteUntilAllBlocked())
+	s.True(d.IsDone())
+}
+
+func (s *WorkflowUnitTest) Test_AwaitAnyForeignFuture() {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		f, _ := NewFuture(ctx)
+		AwaitAny(ctx, []Future{f, foreignFuture{}})
+	})
+	err := d.ExecuteUntilAllBlocked()
+	s.Error(err)
+	s.Contains(err.Error(), "cannot await Future at index 1 that wasn't created with workflow.NewFuture")
+}
+
+func activitiesAsyncWorkflowTest(ctx Context) ([]string, error) {
+	ao := ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	}
+	ctx = WithActivityOptions(ctx, ao)
+	futures := ExecuteActivitiesAsync(ctx, []ExecuteActivityParameters{
+		{Activity: onCompleteHookActivity, Args: []interface{}{"slow"}},
+		{Activity: onCompleteHookActivity, Args: []interface{}{"fail"}},
+		{Activity: onCompleteHookActivity, Args: []interface{}{"fast"}, Options: &ao},
+	})
+	_, errs := AwaitAll(ctx, futures)
+
+	var result []string
+	for i, f := range futures {
+		if errs[i] != nil {
+			result = append(result, errs[i].Error())
+			continue
+		}
+		var r string
+		if err := f.Get(ctx, &r); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivitiesAsyncWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.OnActivity(onCompleteHookActivity, "slow").After(time.Hour).Return("slow-done", nil)
+	env.OnActivity(onCompleteHookActivity, mock.Anything).Return(onCompleteHookActivity)
+	env.ExecuteWorkflow(activitiesAsyncWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"slow-done", "failed", "fast-done"}, result)
+}
+
+func activitiesAsyncRetryPolicyWorkflowTest(ctx Context) ([]string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	})
+	retryPolicy := &RetryPolicy{
+		InitialInterval:          time.Second,
+		BackoffCoefficient:       2,
+		MaximumAttempts:          3,
+		NonRetriableErrorReasons: []string{"bad input"},
+	}
+	futures := ExecuteActivitiesAsync(ctx, []ExecuteActivityParameters{
+		{Activity: flakyActivity, Args: []interface{}{"flaky"}, RetryPolicy: retryPolicy},
+		{Activity: flakyActivity, Args: []interface{}{"bad"}, RetryPolicy: retryPolicy},
+	})
+	var result []string
+	for _, f := range futures {
+		var r string
+		if err := f.Get(ctx, &r); err != nil {
+			r = err.Error()
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivitiesAsyncRetryPolicyWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "flaky").Return("", NewCustomError("flaky")).Twice()
+	env.OnActivity(flakyActivity, "flaky").Return("flaky-done", nil).Once()
+	env.OnActivity(flakyActivity, "bad").Return("", NewCustomError("bad input")).Once()
+	env.ExecuteWorkflow(activitiesAsyncRetryPolicyWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	env.AssertExpectations(s.T())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"flaky-done", "bad input"}, result)
+}
+
+func activitiesWorkflowTest(ctx Context) ([]string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		WaitForCancellation:    true,
+	})
+	params := []ExecuteActivityParameters{
+		{Activity: onCompleteHookActivity, Args: []interface{}{"slow"}},
+		{Activity: onCompleteHookActivity, Args: []interface{}{"fail"}},
+		{Activity: onCompleteHookActivity, Args: []interface{}{"fast"}},
+	}
+	var result []string
+	appendResults := func(results [][]byte, errs []error) error {
+		for i := range params {
+			if errs[i] != nil {
+				result = append(result, fmt.Sprintf("%v-canceled-%v", errs[i], IsCanceledError(errs[i])))
+				continue
+			}
+			var r string
+			if err := getDataConverterFromWorkflowContext(ctx).FromData(results[i], &r); err != nil {
+				return err
+			}
+			result = append(result, r)
+		}
+		return nil
+	}
+	if err := appendResults(ExecuteActivities(ctx, params)); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancel := WithCancel(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		cancel()
+	})
+	if err := appendResults(ExecuteActivities(cancelCtx, params)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivitiesWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.OnActivity(onCompleteHookActivity, "slow").After(time.Hour).Return("slow-done", nil)
+	env.OnActivity(onCompleteHookActivity, mock.Anything).Return(onCompleteHookActivity)
+	env.ExecuteWorkflow(activitiesWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{
+		"slow-done", "failed-canceled-false", "fast-done",
+		"CanceledError-canceled-true", "failed-canceled-false", "fast-done",
+	}, result)
+}
+
+func activityCancellationGracePeriodWorkflowTest(ctx Context) (string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout:  time.Minute,
+		StartToCloseTimeout:     time.Hour * 2,
+		WaitForCancellation:     true,
+		CancellationGracePeriod: time.Hour, // overridden by the parameters
+	})
+	cancelCtx, cancel := WithCancel(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		cancel()
+	})
+	_, errs := ExecuteActivities(cancelCtx, []ExecuteActivityParameters{
+		{Activity: onCompleteHookActivity, Args: []interface{}{"slow"}, CancellationGracePeriodSeconds: 30},
+	})
+	return fmt.Sprintf("%v-canceled-%v", errs[0], IsCanceledError(errs[0])), nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivityCancellationGracePeriodWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.OnActivity(onCompleteHookActivity, "slow").After(time.Hour).Return("slow-done", nil)
+	var scheduled []time.Duration
+	env.SetOnTimerScheduledListener(func(timerID string, duration time.Duration) {
+		scheduled = append(scheduled, duration)
+	})
+	env.ExecuteWorkflow(activityCancellationGracePeriodWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("CanceledError-canceled-true", result)
+	// the grace period timer is started when the activity gets canceled
+	s.Equal([]time.Duration{time.Minute, 30 * time.Second}, scheduled)
+}
+
+func activityOptionsCancellationGracePeriodWorkflowTest(ctx Context) (string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout:  time.Minute,
+		StartToCloseTimeout:     time.Hour * 2,
+		WaitForCancellation:     true,
+		CancellationGracePeriod: 30 * time.Second,
+	})
+	cancelCtx, cancel := WithCancel(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		cancel()
+	})
+	err := ExecuteActivity(cancelCtx, onCompleteHookActivity, "slow").Get(ctx, nil)
+	return fmt.Sprintf("%v-canceled-%v", err, IsCanceledError(err)), nil
+}
+
+func (s *WorkflowUnitTest) Test_ActivityOptionsCancellationGracePeriodWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(onCompleteHookActivity)
+	env.OnActivity(onCompleteHookActivity, "slow").After(time.Hour).Return("slow-done", nil)
+	var scheduled []time.Duration
+	env.SetOnTimerScheduledListener(func(timerID string, duration time.Duration) {
+		scheduled = append(scheduled, duration)
+	})
+	env.ExecuteWorkflow(activityOptionsCancellationGracePeriodWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("CanceledError-canceled-true", result)
+	// the grace period timer is started when the activity gets canceled
+	s.Equal([]time.Duration{time.Minute, 30 * time.Second}, scheduled)
+}
+
+func firstDecisionWorkflowTest(ctx Context) ([]bool, error) {
+	var result []bool
+	result = append(result, IsFirstDecision(ctx))
+	Go(ctx, func(ctx Context) {
+		result = append(result, IsFirstDecision(ctx))
+	})
+	Sleep(ctx, time.Minute)
+	result = append(result, IsFirstDecision(ctx))
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_FirstDecisionWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(firstDecisionWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []bool
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]bool{true, true, false}, result)
+}
+
+func awaitAnyWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	slow := NewTimer(ctx, time.Hour)
+	fast, settable := NewFuture(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		settable.Set("fast", nil)
+	})
+	index, value, err := AwaitAny(ctx, []Future{slow, fast})
+	result = append(result, fmt.Sprintf("%v-%v-%v", index, value, err))
+
+	cancelCtx, cancel := WithCancel(ctx)
+	Go(ctx, func(ctx Context) {
+		Sleep(ctx, time.Minute)
+		cancel()
+	})
+	index, _, err = AwaitAny(cancelCtx, []Future{slow})
+	result = append(result, fmt.Sprintf("%v-%v", index, IsCanceledError(err)))
+
+	index, _, err = AwaitAny(ctx, []Future{fast, slow})
+	result = append(result, fmt.Sprintf("%v-%v", index, err))
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_AwaitAnyWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(awaitAnyWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"1-fast-<nil>", "-1-true", "0-<nil>"}, result)
+}
+
+func sortedChunkActivity(chunk []int) ([]int, error) {
+	if len(chunk) == 0 {
+		return nil, NewCustomError("empty chunk")
+	}
+	return chunk, nil
+}
+
+func mergeSortedResultsWorkflowTest(ctx Context, chunks [][]int) ([]int, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	})
+	var futures []Future
+	for _, chunk := range chunks {
+		futures = append(futures, ExecuteActivity(ctx, sortedChunkActivity, chunk))
+	}
+	toInt := func(v interface{}) int {
+		// values are decoded from JSON
+		n, _ := v.(json.Number).Int64()
+		return int(n)
+	}
+	merged, err := MergeSortedResults(ctx, futures, func(a, b interface{}) bool {
+		return toInt(a) < toInt(b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []int
+	for _, v := range merged {
+		result = append(result, toInt(v))
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_MergeSortedResultsWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(sortedChunkActivity)
+	env.ExecuteWorkflow(mergeSortedResultsWorkflowTest, [][]int{{1, 4, 7, 10}, {2, 5, 8}, {3, 4, 6, 9, 11}})
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []int
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]int{1, 2, 3, 4, 4, 5, 6, 7, 8, 9, 10, 11}, result)
+
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(sortedChunkActivity)
+	env.ExecuteWorkflow(mergeSortedResultsWorkflowTest, [][]int{{1}, {}, {2}})
+	s.True(env.IsWorkflowCompleted())
+	s.Error(env.GetWorkflowError())
+	s.Contains(env.GetWorkflowError().Error(), "empty chunk")
+}
+
+func flakyActivity(input string) (string, error) {
+	return input + "-done", nil
+}
+
+func activityWithRetryWorkflow(ctx Context, input string) (string, error) {
+	ao := ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	}
+	ctx = WithActivityOptions(ctx, ao)
+	retryPolicy := RetryPolicy{
+		InitialInterval:          time.Second,
+		BackoffCoefficient:       2,
+		MaximumAttempts:          3,
+		NonRetriableErrorReasons: []string{"bad input"},
+	}
+	var result string
+	err := ExecuteActivityWithRetry(ctx, retryPolicy, flakyActivity, input).Get(ctx, &result)
+	return result, err
+}
+
+func (s *WorkflowUnitTest) Test_ActivityWithRetryWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "flaky").Return("", NewCustomError("flaky")).Twice()
+	env.OnActivity(flakyActivity, "flaky").Return("flaky-done", nil).Once()
+	env.ExecuteWorkflow(activityWithRetryWorkflow, "flaky")
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("flaky-done", result)
+	env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowUnitTest) Test_ActivityWithRetryWorkflow_MaximumAttempts() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "flaky").Return("", NewCustomError("flaky")).Times(3)
+	env.ExecuteWorkflow(activityWithRetryWorkflow, "flaky")
+	s.True(env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "flaky")
+	env.AssertExpectations(s.T())
+}
+
+func retryActivityWorkflowTest(ctx Context, input string) (string, error) {
+	params := ExecuteActivityParameters{
+		Activity: flakyActivity,
+		Args:     []interface{}{input},
+		Options: &ActivityOptions{
+			ScheduleToStartTimeout: time.Minute,
+			StartToCloseTimeout:    time.Minute,
+		},
+	}
+	retryPolicy := RetryPolicy{
+		InitialInterval:          time.Second,
+		BackoffCoefficient:       2,
+		MaximumAttempts:          3,
+		NonRetriableErrorReasons: []string{"bad input"},
+	}
+	start := Now(ctx)
+	data, err := RetryActivity(ctx, params, retryPolicy)
+	if err != nil {
+		return "", err
+	}
+	var result string
+	if err := getDataConverterFromWorkflowContext(ctx).FromData(data, &result); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v after %v", result, Now(ctx).Sub(start)), nil
+}
+
+func (s *WorkflowUnitTest) Test_RetryActivityWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "flaky").Return("", NewCustomError("flaky")).Twice()
+	env.OnActivity(flakyActivity, "flaky").Return("flaky-done", nil).Once()
+	env.ExecuteWorkflow(retryActivityWorkflowTest, "flaky")
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal("flaky-done after 3s", result)
+	env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowUnitTest) Test_RetryActivityWorkflow_NonRetriable() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "bad").Return("", NewCustomError("bad input")).Once()
+	env.ExecuteWorkflow(retryActivityWorkflowTest, "bad")
+	s.True(env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	s.Error(err)
+	s.Contains(err.Error(), "bad input")
+	env.AssertExpectations(s.T())
+}
+
+func backoffUntilDeadlineWorkflowTest(ctx Context, succeedAt int) (string, error) {
+	policy := RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2,
+	}
+	start := Now(ctx)
+	var attempts int
+	err := BackoffUntilDeadline(ctx, policy, start.Add(10*time.Second), func() (bool, error) {
+		attempts++
+		return attempts == succeedAt, nil
+	})
+	var timeout bool
+	if err != nil {
+		if _, timeout = err.(*TimeoutError); !timeout {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("attempts=%v timeout=%v after %v", attempts, timeout, Now(ctx).Sub(start)), nil
+}
+
+func (s *WorkflowUnitTest) Test_BackoffUntilDeadlineWorkflow() {
+	for succeedAt, expected := range map[int]string{
+		3: "attempts=3 timeout=false after 3s",
+		// backoffs of 1s, 2s and 4s fit before the deadline, the next one of 8s does not
+		0: "attempts=4 timeout=true after 7s",
+	} {
+		env := s.NewTestWorkflowEnvironment()
+		env.ExecuteWorkflow(backoffUntilDeadlineWorkflowTest, succeedAt)
+		s.True(env.IsWorkflowCompleted())
+		s.NoError(env.GetWorkflowError())
+		var result string
+		s.NoError(env.GetWorkflowResult(&result))
+		s.Equal(expected, result)
+	}
+}
+
+func deadlineWorkflowTest(ctx Context) ([]string, error) {
+	var result []string
+	report := func(name string, ctx Context) {
+		remaining, ok := RemainingDeadline(ctx)
+		result = append(result, fmt.Sprintf("%v %v %v %v", name, remaining, ok, ctx.Err()))
+	}
+	report("workflow", ctx)
+
+	deadlineCtx, cancel := WithTimeout(ctx, time.Hour)
+	defer cancel()
+	// a later deadline does not extend the one of the parent
+	laterCtx, _ := WithTimeout(deadlineCtx, 2*time.Hour)
+	_ = Sleep(ctx, 10*time.Minute)
+	report("deadline", deadlineCtx)
+	report("later", laterCtx)
+
+	start := Now(ctx)
+	err := Sleep(laterCtx, 2*time.Hour)
+	result = append(result, fmt.Sprintf("sleep %v canceled=%v", Now(ctx).Sub(start), IsCanceledError(err)))
+	report("expired", deadlineCtx)
+
+	// canceling before the deadline cancels its timer
+	canceledCtx, cancel := WithDeadline(ctx, Now(ctx).Add(time.Hour))
+	cancel()
+	report("canceled", canceledCtx)
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_DeadlineWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(deadlineWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{
+		"workflow 0s false <nil>",
+		"deadline 50m0s true <nil>",
+		"later 50m0s true <nil>",
+		"sleep 50m0s canceled=true",
+		"expired 0s true TimeoutType: SCHEDULE_TO_CLOSE",
+		"canceled 1h0m0s true CanceledError",
+	}, result)
+}
+
+func callActivityTestActivity(ctx context.Context, msg string) (string, error) {
+	info := GetActivityInfo(ctx)
+	return fmt.Sprintf("%v/%v/%v", msg, info.TaskList, info.HeartbeatTimeout), nil
+}
+
+func callActivityWorkflowTest(ctx Context) ([]string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		TaskList:               "default-tl",
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+		HeartbeatTimeout:       time.Second,
+	})
+	var result []string
+	for _, activityCtx := range []Context{ctx, WithHeartbeatTimeout(ctx, time.Minute)} {
+		input, err := encodeArg(getDataConverterFromWorkflowContext(ctx), "hi")
+		if err != nil {
+			return nil, err
+		}
+		data, err := CallActivity(activityCtx, "callActivity", input)
+		if err != nil {
+			return nil, err
+		}
+		var r string
+		if err := getDataConverterFromWorkflowContext(ctx).FromData(data, &r); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *WorkflowUnitTest) Test_CallActivityWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivityWithOptions(callActivityTestActivity, RegisterActivityOptions{Name: "callActivity"})
+	env.ExecuteWorkflow(callActivityWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"hi/default-tl/1s", "hi/default-tl/1m0s"}, result)
+}
+
 const (
 	memoTestKey = "testKey"
 	memoTestVal = "testVal"
@@ -950,6 +1988,30 @@ func (s *WorkflowUnitTest) Test_MemoWorkflow() {
 	s.Equal(memoTestVal, result)
 }
 
+func workflowStartTimeWorkflowTest(ctx Context) (time.Duration, error) {
+	startTime := GetWorkflowInfo(ctx).WorkflowStartTime
+	if startTime.IsZero() {
+		return 0, errors.New("workflow start time is not set")
+	}
+	if err := Sleep(ctx, time.Hour); err != nil {
+		return 0, err
+	}
+	if !GetWorkflowInfo(ctx).WorkflowStartTime.Equal(startTime) {
+		return 0, errors.New("workflow start time changed")
+	}
+	return Now(ctx).Sub(startTime), nil
+}
+
+func (s *WorkflowUnitTest) Test_WorkflowStartTimeWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(workflowStartTimeWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var elapsed time.Duration
+	s.NoError(env.GetWorkflowResult(&elapsed))
+	s.Equal(time.Hour, elapsed)
+}
+
 func sleepWorkflow(ctx Context, input time.Duration) (int, error) {
 	if err := Sleep(ctx, input); err != nil {
 		return 0, err
@@ -1181,6 +2243,53 @@ func (s *WorkflowUnitTest) Test_WaitGroupWorkflowTest() {
 	s.Equal(n, total)
 }
 
+func mutexWorkflowTest(ctx Context, n int) (int, error) {
+	mutex := NewMutex(ctx)
+	waitGroup := NewWaitGroup(ctx)
+	counter := 0
+	for i := 0; i < n; i++ {
+		waitGroup.Add(1)
+		Go(ctx, func(ctx Context) {
+			defer waitGroup.Done()
+			mutex.Lock(ctx)
+			defer mutex.Unlock()
+			// read, yield and write back, without the mutex other coroutines would interleave here
+			value := counter
+			Sleep(ctx, time.Second)
+			counter = value + 1
+		})
+	}
+	waitGroup.Wait(ctx)
+	return counter, nil
+}
+
+func (s *WorkflowUnitTest) Test_MutexWorkflowTest() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(mutexWorkflowTest)
+	env.ExecuteWorkflow(mutexWorkflowTest, 10)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var total int
+	s.NoError(env.GetWorkflowResult(&total))
+	s.Equal(10, total)
+}
+
+func mutexUnlockOfUnlockedPanicsWorkflowTest(ctx Context) error {
+	NewMutex(ctx).Unlock()
+	return nil
+}
+
+func (s *WorkflowUnitTest) Test_MutexUnlockOfUnlockedPanicsWorkflowTest() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(mutexUnlockOfUnlockedPanicsWorkflowTest)
+	env.ExecuteWorkflow(mutexUnlockOfUnlockedPanicsWorkflowTest)
+	s.True(env.IsWorkflowCompleted())
+
+	resultErr := env.GetWorkflowError().(*PanicError)
+	s.EqualValues("unlock of unlocked Mutex", resultErr.Error())
+}
+
 var _ WorkflowInterceptorFactory = (*tracingInterceptorFactory)(nil)
 
 type tracingInterceptorFactory struct {
@@ -1213,3 +2322,27 @@ func (t *tracingInterceptor) ExecuteWorkflow(ctx Context, workflowType string, a
 	t.trace = append(t.trace, "ExecuteWorkflow "+workflowType+" end")
 	return result
 }
+
+func TestPartition(t *testing.T) {
+	items := []interface{}{"a", "b", "c", "d", "e", "f", "g"}
+	expected := [][]interface{}{{"a", "b", "c"}, {"d", "e"}, {"f", "g"}}
+	for i := 0; i < 5; i++ {
+		require.Equal(t, expected, Partition(items, 3))
+	}
+
+	for parts := 1; parts <= 10; parts++ {
+		var flattened []interface{}
+		for _, part := range Partition(items, parts) {
+			flattened = append(flattened, part...)
+		}
+		require.Equal(t, items, flattened, "parts=%v", parts)
+		require.Len(t, Partition(items, parts), parts)
+	}
+
+	// appending to a partition does not overwrite the next one
+	partitions := Partition(items, 2)
+	_ = append(partitions[0], "x")
+	require.Equal(t, "e", partitions[1][0])
+
+	require.Panics(t, func() { Partition(items, 0) })
+}
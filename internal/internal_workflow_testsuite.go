@@ -155,6 +155,7 @@ type (
 		onTimerCancelledListener         func(timerID string)
 
 		cronMaxIterations int
+		livenessDetector  func(snapshot DispatcherSnapshot) error
 	}
 
 	// testWorkflowEnvironmentImpl is the environment that runs the workflow/activity unit tests.
@@ -182,6 +183,9 @@ type (
 		executionTimeout time.Duration
 
 		heartbeatDetails []byte
+		decisionsStarted int
+		resumingMock     bool  // the workflow resumes from the mock check, which is not a decision of its own
+		historyLength    int64 // approximates the number of events a Cadence server would have recorded
 
 		workerStopChannel  chan struct{}
 		sessionEnvironment *testSessionEnvironmentImpl
@@ -189,6 +193,11 @@ type (
 		cronSchedule   string
 		cronIterations int
 		workflowInput  []byte
+
+		sideEffectFixture map[int][]byte
+		sideEffectCalls   int
+
+		livenessSnapshots map[string]CoroutineSnapshot // last snapshot of each coroutine, keyed by name
 	}
 
 	testSessionEnvironmentImpl struct {
@@ -474,6 +483,7 @@ func (env *testWorkflowEnvironmentImpl) executeWorkflowInternal(delayStart time.
 		panic(fmt.Sprintf("Current TestWorkflowEnvironment is used to execute %v. Please create a new TestWorkflowEnvironment for %v.", env.workflowInfo.WorkflowType.Name, workflowType))
 	}
 	env.workflowInfo.WorkflowType.Name = workflowType
+	env.workflowInfo.WorkflowStartTime = env.Now()
 	env.locker.Unlock()
 
 	workflowDefinition, err := env.getWorkflowDefinition(env.workflowInfo.WorkflowType)
@@ -626,7 +636,41 @@ func (env *testWorkflowEnvironmentImpl) executeLocalActivity(
 
 func (env *testWorkflowEnvironmentImpl) startDecisionTask() {
 	if !env.isTestCompleted {
+		if !env.resumingMock {
+			env.decisionsStarted++
+			// DecisionTaskCompleted of the previous decision, DecisionTaskScheduled and DecisionTaskStarted
+			env.historyLength += 3
+		}
 		env.workflowDef.OnDecisionTaskStarted()
+		env.detectLiveness()
+		env.resumingMock = false
+	}
+}
+
+func (env *testWorkflowEnvironmentImpl) detectLiveness() {
+	if env.livenessDetector == nil || env.isTestCompleted || env.resumingMock {
+		return
+	}
+	def, ok := env.workflowDef.(*syncWorkflowDefinition)
+	if !ok {
+		return
+	}
+	d, ok := def.dispatcher.(*dispatcherImpl)
+	if !ok {
+		return
+	}
+	// A coroutine blocked with the same stack trace as in the previous snapshot has not moved since then.
+	snapshot := d.snapshot(env.Now())
+	previous := env.livenessSnapshots
+	env.livenessSnapshots = make(map[string]CoroutineSnapshot, len(snapshot.Coroutines))
+	for i, c := range snapshot.Coroutines {
+		if p, ok := previous[c.Name]; ok && p.StackTrace == c.StackTrace {
+			snapshot.Coroutines[i].BlockedSince = p.BlockedSince
+		}
+		env.livenessSnapshots[c.Name] = snapshot.Coroutines[i]
+	}
+	if err := env.livenessDetector(snapshot); err != nil {
+		env.Complete(nil, err)
 	}
 }
 
@@ -1019,6 +1063,8 @@ func (env *testWorkflowEnvironmentImpl) ExecuteActivity(parameters executeActivi
 		activityID = *parameters.ActivityID
 	}
 	activityInfo := &activityInfo{activityID: activityID}
+	// ActivityTaskScheduled, ActivityTaskStarted and the event that closes the activity
+	env.historyLength += 3
 	task := newTestActivityTask(
 		defaultTestWorkflowID,
 		defaultTestRunID,
@@ -1407,6 +1453,8 @@ func (w *workflowExecutorWrapper) Execute(ctx Context, input []byte) (result []b
 		// the returned mockRet by calling executeMock() later in the main thread after it is send over via mockReadyChannel.
 		mockRet := m.getMockReturn(ctxCopy, input)
 		env.postCallback(func() {
+			// resuming from the mock check is still part of the decision that started the workflow
+			env.resumingMock = true
 			mockReadyChannel.SendAsync(mockRet)
 		}, true /* true to trigger the dispatcher for this workflow so it resume from mockReadyChannel block*/)
 	}()
@@ -1707,6 +1755,8 @@ func (env *testWorkflowEnvironmentImpl) newTimer(d time.Duration, callback resul
 }
 
 func (env *testWorkflowEnvironmentImpl) NewTimer(d time.Duration, callback resultHandler) *timerInfo {
+	// TimerStarted and TimerFired or TimerCanceled
+	env.historyLength += 2
 	return env.newTimer(d, callback, true)
 }
 
@@ -1807,6 +1857,14 @@ func (env *testWorkflowEnvironmentImpl) IsReplaying() bool {
 	return false
 }
 
+func (env *testWorkflowEnvironmentImpl) IsFirstDecision() bool {
+	return env.decisionsStarted <= 1
+}
+
+func (env *testWorkflowEnvironmentImpl) GetHistoryLength() int64 {
+	return env.historyLength
+}
+
 func (env *testWorkflowEnvironmentImpl) IsCron() bool {
 	// this test environment never replay
 	return env.workflowInfo.CronSchedule != nil && len(*env.workflowInfo.CronSchedule) > 0
@@ -1880,6 +1938,13 @@ func (env *testWorkflowEnvironmentImpl) executeChildWorkflowWithDelay(delayStart
 }
 
 func (env *testWorkflowEnvironmentImpl) SideEffect(f func() ([]byte, error), callback resultHandler) {
+	call := env.sideEffectCalls
+	env.sideEffectCalls++
+	if result, ok := env.sideEffectFixture[call]; ok {
+		env.logger.Debug("SideEffect returning fixture result.", zap.Int("SideEffectCall", call))
+		callback(result, nil)
+		return
+	}
 	callback(f())
 }
 
@@ -1907,6 +1972,14 @@ func (env *testWorkflowEnvironmentImpl) GetVersion(changeID string, minSupported
 	return maxSupported
 }
 
+func (env *testWorkflowEnvironmentImpl) GetVersions() map[string]Version {
+	versions := make(map[string]Version, len(env.changeVersions))
+	for changeID, version := range env.changeVersions {
+		versions[changeID] = version
+	}
+	return versions
+}
+
 func (env *testWorkflowEnvironmentImpl) getMockedVersion(mockedChangeID, changeID string, minSupported, maxSupported Version) (Version, bool) {
 	mockMethod := getMockMethodForGetVersion(mockedChangeID)
 	if _, ok := env.expectedMockCalls[mockMethod]; !ok {
@@ -2015,6 +2088,7 @@ func (env *testWorkflowEnvironmentImpl) signalWorkflow(name string, input interf
 		panic(err)
 	}
 	env.postCallback(func() {
+		env.historyLength++ // WorkflowExecutionSignaled
 		env.signalHandler(name, data)
 	}, startDecisionTask)
 }
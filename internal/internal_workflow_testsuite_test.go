@@ -1265,6 +1265,31 @@ func (s *WorkflowTestSuiteUnitTest) Test_GetVersion() {
 	env.AssertExpectations(s.T())
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_GetVersionHistory() {
+	workflowFn := func(ctx Context) (map[string]Version, error) {
+		if len(GetVersionHistory(ctx)) != 0 {
+			return nil, errors.New("no version expected before GetVersion is called")
+		}
+		GetVersion(ctx, "change_a", DefaultVersion, 1)
+		GetVersion(ctx, "change_b", DefaultVersion, 3)
+		// a second call for the same changeID keeps the recorded version
+		GetVersion(ctx, "change_b", DefaultVersion, 4)
+		history := GetVersionHistory(ctx)
+		history["change_c"] = 1 // must not leak into the workflow
+		return GetVersionHistory(ctx), nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var history map[string]Version
+	s.NoError(env.GetWorkflowResult(&history))
+	s.Equal(map[string]Version{"change_a": 1, "change_b": 3}, history)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_MockGetVersion() {
 	oldActivity := func(ctx context.Context, msg string) (string, error) {
 		return "hello" + "_" + msg, nil
@@ -1669,6 +1694,137 @@ func (s *WorkflowTestSuiteUnitTest) Test_ChildWorkflowContextPropagation() {
 	s.NoError(env.GetWorkflowError())
 }
 
+func (s *WorkflowTestSuiteUnitTest) Test_ContextPropagatorsOrder() {
+	// propagation order as seen by the receiving side: the header written by the injecting propagators, and the
+	// names of the extracting propagators
+	propagation := func(value func(key interface{}) interface{}) string {
+		return fmt.Sprintf("%v/%v", value(chainHeaderKey{}), value(chainKey{}))
+	}
+	activityFn := func(ctx context.Context) (string, error) {
+		return propagation(ctx.Value), nil
+	}
+	childWorkflowFn := func(ctx Context) (string, error) {
+		return propagation(ctx.Value), nil
+	}
+	workflowFn := func(ctx Context) ([]string, error) {
+		ctx = WithChildWorkflowOptions(ctx, ChildWorkflowOptions{ExecutionStartToCloseTimeout: time.Hour})
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			ScheduleToStartTimeout: time.Minute,
+			StartToCloseTimeout:    time.Minute,
+		})
+		var child, activity string
+		if err := ExecuteChildWorkflow(ctx, childWorkflowFn).Get(ctx, &child); err != nil {
+			return nil, err
+		}
+		if err := ExecuteActivity(ctx, activityFn).Get(ctx, &activity); err != nil {
+			return nil, err
+		}
+		return []string{propagation(ctx.Value), child, activity}, nil
+	}
+
+	s.SetContextPropagators([]ContextPropagator{&chainTestPropagator{name: "a"}, &chainTestPropagator{name: "b"}})
+	s.SetHeader(&shared.Header{
+		Fields: map[string][]byte{
+			"chain": []byte("client-"),
+		},
+	})
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.RegisterWorkflow(childWorkflowFn)
+	env.RegisterActivity(activityFn)
+	env.ExecuteWorkflow(workflowFn)
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"client-/ab", "ab/ab", "ab/ab"}, result)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_LivenessDetector() {
+	workflowFn := func(ctx Context, stuckAfter int) (int, error) {
+		ch := NewChannel(ctx)
+		var polled int
+		GoNamed(ctx, "poller", func(ctx Context) {
+			for ch.Receive(ctx, nil) {
+				polled++
+				// blocking elsewhere between two receives shows that the poller moves
+				Sleep(ctx, time.Second)
+			}
+		})
+		for i := 0; i < 180; i++ {
+			if i < stuckAfter {
+				ch.Send(ctx, i)
+			}
+			Sleep(ctx, time.Minute)
+		}
+		return polled, nil
+	}
+	detector := func(snapshot DispatcherSnapshot) error {
+		for _, c := range snapshot.Coroutines {
+			if c.Name == "poller" && snapshot.Now.Sub(c.BlockedSince) > time.Hour {
+				return fmt.Errorf("poller blocked since %v", snapshot.Now.Sub(c.BlockedSince))
+			}
+		}
+		return nil
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.SetLivenessDetector(detector)
+	env.ExecuteWorkflow(workflowFn, 180)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var polled int
+	s.NoError(env.GetWorkflowResult(&polled))
+	s.Equal(180, polled)
+
+	env = s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.SetLivenessDetector(detector)
+	env.ExecuteWorkflow(workflowFn, 30)
+	s.True(env.IsWorkflowCompleted())
+	s.EqualError(env.GetWorkflowError(), "poller blocked since 1h0m59s")
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_SideEffectFixture() {
+	var invoked []int
+	workflowFn := func(ctx Context) ([]int, error) {
+		var result []int
+		for i := 0; i < 3; i++ {
+			var r int
+			v := SideEffect(ctx, func(ctx Context) interface{} {
+				invoked = append(invoked, i)
+				return i
+			})
+			if err := v.Get(&r); err != nil {
+				return nil, err
+			}
+			result = append(result, r)
+		}
+		return result, nil
+	}
+
+	fixture := make(map[int][]byte)
+	for call, value := range map[int]int{0: 10, 2: 12} {
+		data, err := encodeArg(nil, value)
+		s.NoError(err)
+		fixture[call] = data
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.SetSideEffectFixture(fixture)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []int
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]int{10, 1, 12}, result)
+	// only the call without a recorded result invoked its side effect function
+	s.Equal([]int{1}, invoked)
+}
+
 func (s *WorkflowTestSuiteUnitTest) Test_ActivityFullyQualifiedName() {
 	// TODO (madhu): Add this back once test workflow environment is able to handle panics gracefully
 	// Right now, the panic happens in a different goroutine and there is no way to catch it
@@ -0,0 +1,75 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import "time"
+
+func testPacerWorkflow(ctx Context) ([]time.Duration, error) {
+	start := Now(ctx)
+	pacer := NewPacer(ctx, 10*time.Second)
+	var offsets []time.Duration
+	for i := 0; i < 3; i++ {
+		if err := pacer.Next(ctx); err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, Now(ctx).Sub(start))
+		if i == 1 {
+			// work that takes longer than the interval does not delay the next call any further
+			if err := Sleep(ctx, time.Minute); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return offsets, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_PacerSpacesNextCalls() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testPacerWorkflow)
+	env.ExecuteWorkflow(testPacerWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var offsets []time.Duration
+	s.NoError(env.GetWorkflowResult(&offsets))
+	s.Equal([]time.Duration{0, 10 * time.Second, 70 * time.Second}, offsets)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_PacerCanceled() {
+	workflowFn := func(ctx Context) error {
+		pacer := NewPacer(ctx, time.Hour)
+		if err := pacer.Next(ctx); err != nil {
+			return err
+		}
+		ctx, cancel := WithCancel(ctx)
+		Go(ctx, func(ctx Context) {
+			Sleep(ctx, time.Minute)
+			cancel()
+		})
+		return pacer.Next(ctx)
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.True(IsCanceledError(env.GetWorkflowError()))
+}
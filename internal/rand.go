@@ -0,0 +1,86 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sort"
+)
+
+type (
+	// Rand is a source of pseudo-random numbers that is safe to use in workflow code. See NewRand.
+	Rand interface {
+		// Int63 returns a non-negative pseudo-random 63-bit integer as an int64.
+		Int63() int64
+		// Intn returns a non-negative pseudo-random number in [0,n). It panics if n <= 0.
+		Intn(n int) int
+		// Float64 returns a pseudo-random number in [0.0,1.0).
+		Float64() float64
+	}
+)
+
+// NewRand creates a source of pseudo-random numbers for workflow code, which must not use math/rand as it breaks the
+// determinism of replay. The source is seeded from the run ID of the workflow and from the number of sources created
+// before it by the workflow, so the same run always gets the same sequences, including on replay, while different
+// runs and different sources of a run get different ones:
+//  r := workflow.NewRand(ctx)
+//  jitter := time.Duration(r.Intn(60)) * time.Second
+// The numbers are not suitable for security sensitive work.
+func NewRand(ctx Context) Rand {
+	state := getState(ctx)
+	state.dispatcher.randSequence++
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(GetWorkflowInfo(ctx).WorkflowExecution.RunID))
+	seed := int64(h.Sum64()) + int64(state.dispatcher.randSequence)
+	return rand.New(rand.NewSource(seed))
+}
+
+// WeightedTaskList picks one of the task lists, which map to their weights, with a probability proportional to its
+// weight. The pick is made with a source created by NewRand, so it is replay safe. Use it to spread activities over
+// several task lists:
+//  ao.TaskList = workflow.WeightedTaskList(ctx, map[string]int{"primary": 3, "secondary": 1})
+// Task lists with a non-positive weight are never picked. It returns an empty string if no task list has a positive
+// weight.
+func WeightedTaskList(ctx Context, lists map[string]int) string {
+	var names []string
+	total := 0
+	for name, weight := range lists {
+		if weight > 0 {
+			names = append(names, name)
+			total += weight
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	// Map iteration order is random, sort to walk the weights the same way on replay.
+	sort.Strings(names)
+	n := NewRand(ctx).Intn(total)
+	for _, name := range names {
+		if n < lists[name] {
+			return name
+		}
+		n -= lists[name]
+	}
+	panic("unreachable")
+}
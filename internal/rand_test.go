@@ -0,0 +1,122 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRandWorkflow(ctx Context) ([]int64, error) {
+	var result []int64
+	r1 := NewRand(ctx)
+	r2 := NewRand(ctx)
+	for i := 0; i < 3; i++ {
+		result = append(result, r1.Int63())
+	}
+	for i := 0; i < 3; i++ {
+		result = append(result, r2.Int63())
+	}
+	return result, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) executeRandWorkflow(runID string) []int64 {
+	env := s.NewTestWorkflowEnvironment()
+	env.impl.workflowInfo.WorkflowExecution.RunID = runID
+	env.RegisterWorkflow(testRandWorkflow)
+	env.ExecuteWorkflow(testRandWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []int64
+	s.NoError(env.GetWorkflowResult(&result))
+	return result
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_NewRandDeterministic() {
+	first := s.executeRandWorkflow("run-1")
+	s.Len(first, 6)
+	s.NotEqual(first[:3], first[3:], "sources of the same run must differ")
+	s.Equal(first, s.executeRandWorkflow("run-1"), "the same run must get the same sequences")
+	s.NotEqual(first, s.executeRandWorkflow("run-2"), "different runs must get different sequences")
+}
+
+func TestNewRand_Ranges(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		r := NewRand(ctx)
+		for i := 0; i < 100; i++ {
+			n := r.Intn(10)
+			require.True(t, n >= 0 && n < 10)
+			f := r.Float64()
+			require.True(t, f >= 0 && f < 1)
+			require.True(t, r.Int63() >= 0)
+		}
+		require.Panics(t, func() { r.Intn(0) })
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
+func testWeightedTaskListWorkflow(ctx Context) ([]string, error) {
+	var result []string
+	for i := 0; i < 20; i++ {
+		result = append(result, WeightedTaskList(ctx, map[string]int{"a": 1, "b": 2, "c": 3}))
+	}
+	return result, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_WeightedTaskListDeterministic() {
+	execute := func() []string {
+		env := s.NewTestWorkflowEnvironment()
+		env.impl.workflowInfo.WorkflowExecution.RunID = "run-1"
+		env.RegisterWorkflow(testWeightedTaskListWorkflow)
+		env.ExecuteWorkflow(testWeightedTaskListWorkflow)
+		s.True(env.IsWorkflowCompleted())
+		s.NoError(env.GetWorkflowError())
+		var result []string
+		s.NoError(env.GetWorkflowResult(&result))
+		return result
+	}
+	first := execute()
+	s.Len(first, 20)
+	s.Equal(first, execute(), "the same run must get the same selections")
+}
+
+func TestWeightedTaskList_Distribution(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		const calls = 6000
+		counts := make(map[string]int)
+		for i := 0; i < calls; i++ {
+			counts[WeightedTaskList(ctx, map[string]int{"a": 1, "b": 2, "c": 3, "disabled": 0})]++
+		}
+		require.Len(t, counts, 3)
+		require.InDelta(t, calls*1/6, counts["a"], calls*0.03)
+		require.InDelta(t, calls*2/6, counts["b"], calls*0.03)
+		require.InDelta(t, calls*3/6, counts["c"], calls*0.03)
+
+		require.Equal(t, "", WeightedTaskList(ctx, nil))
+		require.Equal(t, "", WeightedTaskList(ctx, map[string]int{"a": 0, "b": -1}))
+		require.Equal(t, "b", WeightedTaskList(ctx, map[string]int{"a": 0, "b": 5}))
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
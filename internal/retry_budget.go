@@ -0,0 +1,77 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// RetryBudget caps the total number of retries of the activities of a workflow, as opposed to the retry policy
+	// of each activity. See NewRetryBudget.
+	RetryBudget interface {
+		// TryConsume consumes one retry from the budget, it returns false when the budget is exhausted.
+		TryConsume() bool
+		// Remaining returns the number of retries left in the budget.
+		Remaining() int
+	}
+
+	retryBudgetImpl struct {
+		remaining int
+	}
+)
+
+const retryBudgetContextKey contextKey = "retryBudget"
+
+// NewRetryBudget creates a RetryBudget allowing retries retries in total. Attach it with WithRetryBudget to the
+// contexts of the activities that share it:
+//  budget := workflow.NewRetryBudget(ctx, 10)
+//  ctx = workflow.WithRetryBudget(ctx, budget)
+//  f1 := workflow.ExecuteActivityWithRetry(ctx, policy, activityA)
+//  f2 := workflow.ExecuteActivityWithRetry(ctx, policy, activityB)
+// The budget only holds workflow state, so it is deterministic. NewRetryBudget panics if retries is negative.
+func NewRetryBudget(ctx Context, retries int) RetryBudget {
+	if retries < 0 {
+		panic("negative retries for NewRetryBudget")
+	}
+	return &retryBudgetImpl{remaining: retries}
+}
+
+// WithRetryBudget returns a copy of ctx whose activities executed with ExecuteActivityWithRetry or RetryActivity
+// consume a retry from budget before every retry. Once budget is exhausted, a failed attempt is not retried anymore,
+// its error is returned right away, even if the retry policy of the activity would allow more attempts.
+func WithRetryBudget(ctx Context, budget RetryBudget) Context {
+	return WithValue(ctx, retryBudgetContextKey, budget)
+}
+
+func getRetryBudget(ctx Context) RetryBudget {
+	budget, _ := ctx.Value(retryBudgetContextKey).(RetryBudget)
+	return budget
+}
+
+func (b *retryBudgetImpl) TryConsume() bool {
+	if b.remaining == 0 {
+		return false
+	}
+	b.remaining--
+	return true
+}
+
+func (b *retryBudgetImpl) Remaining() int {
+	return b.remaining
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testRetryBudgetWorkflow(ctx Context) ([]string, error) {
+	ctx = WithActivityOptions(ctx, ActivityOptions{
+		ScheduleToStartTimeout: time.Minute,
+		StartToCloseTimeout:    time.Minute,
+	})
+	budget := NewRetryBudget(ctx, 2)
+	ctx = WithRetryBudget(ctx, budget)
+	policy := RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2,
+		MaximumAttempts:    5,
+	}
+	var result []string
+	for _, input := range []string{"first", "second"} {
+		var r string
+		if err := ExecuteActivityWithRetry(ctx, policy, flakyActivity, input).Get(ctx, &r); err != nil {
+			r = err.Error()
+		}
+		result = append(result, r)
+	}
+	if budget.Remaining() != 0 {
+		return nil, NewCustomError("budget not exhausted")
+	}
+	return result, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_RetryBudget() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testRetryBudgetWorkflow)
+	env.RegisterActivity(flakyActivity)
+	env.OnActivity(flakyActivity, "first").Return("", NewCustomError("flaky")).Twice()
+	env.OnActivity(flakyActivity, "first").Return("first-done", nil).Once()
+	// the first activity consumed the whole budget, so the second one is not retried
+	env.OnActivity(flakyActivity, "second").Return("", NewCustomError("flaky")).Once()
+	env.ExecuteWorkflow(testRetryBudgetWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result []string
+	s.NoError(env.GetWorkflowResult(&result))
+	s.Equal([]string{"first-done", "flaky"}, result)
+	env.AssertExpectations(s.T())
+}
+
+func TestNewRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(nil, 1)
+	require.Equal(t, 1, budget.Remaining())
+	require.True(t, budget.TryConsume())
+	require.False(t, budget.TryConsume())
+	require.Equal(t, 0, budget.Remaining())
+	require.Panics(t, func() { NewRetryBudget(nil, -1) })
+}
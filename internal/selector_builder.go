@@ -0,0 +1,103 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// SelectorBuilder accumulates Selector cases so that they can be inspected before the Selector is built.
+	// See NewSelectorBuilder.
+	SelectorBuilder interface {
+		// AddReceive adds a receive case, see Selector.AddReceive.
+		AddReceive(c Channel, f func(c Channel, more bool)) SelectorBuilder
+		// AddSend adds a send case, see Selector.AddSend.
+		AddSend(c Channel, v interface{}, f func()) SelectorBuilder
+		// AddFuture adds a future case, see Selector.AddFuture.
+		AddFuture(future Future, f func(f Future)) SelectorBuilder
+		// AddDefault sets the default case, see Selector.AddDefault. It does not count as a case.
+		AddDefault(f func()) SelectorBuilder
+		// CaseCount returns the number of receive, send and future cases added so far.
+		CaseCount() int
+		// HasDefault returns true if a default case was added.
+		HasDefault() bool
+		// Build creates a new Selector with all the added cases, in the order they were added.
+		// Every call returns a new Selector.
+		Build() Selector
+	}
+
+	selectorBuilderImpl struct {
+		ctx         Context
+		cases       []func(s Selector)
+		defaultFunc func()
+	}
+)
+
+// NewSelectorBuilder creates a builder for a Selector. Unlike a Selector, the builder can report how many cases were
+// added, which lets code that generates cases programmatically detect an empty set that would block forever:
+//  builder := workflow.NewSelectorBuilder(ctx)
+//  for _, f := range futures {
+//    builder.AddFuture(f, handle)
+//  }
+//  if builder.CaseCount() == 0 && !builder.HasDefault() {
+//    return errors.New("nothing to wait for")
+//  }
+//  builder.Build().Select(ctx)
+func NewSelectorBuilder(ctx Context) SelectorBuilder {
+	return &selectorBuilderImpl{ctx: ctx}
+}
+
+func (b *selectorBuilderImpl) AddReceive(c Channel, f func(c Channel, more bool)) SelectorBuilder {
+	b.cases = append(b.cases, func(s Selector) { s.AddReceive(c, f) })
+	return b
+}
+
+func (b *selectorBuilderImpl) AddSend(c Channel, v interface{}, f func()) SelectorBuilder {
+	b.cases = append(b.cases, func(s Selector) { s.AddSend(c, v, f) })
+	return b
+}
+
+func (b *selectorBuilderImpl) AddFuture(future Future, f func(f Future)) SelectorBuilder {
+	b.cases = append(b.cases, func(s Selector) { s.AddFuture(future, f) })
+	return b
+}
+
+func (b *selectorBuilderImpl) AddDefault(f func()) SelectorBuilder {
+	b.defaultFunc = f
+	return b
+}
+
+func (b *selectorBuilderImpl) CaseCount() int {
+	return len(b.cases)
+}
+
+func (b *selectorBuilderImpl) HasDefault() bool {
+	return b.defaultFunc != nil
+}
+
+func (b *selectorBuilderImpl) Build() Selector {
+	s := NewSelector(b.ctx)
+	for _, addCase := range b.cases {
+		addCase(s)
+	}
+	if b.defaultFunc != nil {
+		s.AddDefault(b.defaultFunc)
+	}
+	return s
+}
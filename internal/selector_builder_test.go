@@ -0,0 +1,68 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorBuilder(t *testing.T) {
+	var history []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		builder := NewSelectorBuilder(ctx)
+		require.Equal(t, 0, builder.CaseCount())
+		require.False(t, builder.HasDefault())
+
+		c := NewBufferedChannel(ctx, 1)
+		c.SendAsync("value")
+		f, s := NewFuture(ctx)
+		builder.
+			AddReceive(c, func(c Channel, more bool) {
+				var v string
+				c.Receive(ctx, &v)
+				history = append(history, "receive-"+v)
+			}).
+			AddFuture(f, func(f Future) {
+				var v string
+				require.NoError(t, f.Get(ctx, &v))
+				history = append(history, "future-"+v)
+			})
+		require.Equal(t, 2, builder.CaseCount())
+
+		selector := builder.Build()
+		selector.Select(ctx)
+		s.SetValue("value")
+		selector.Select(ctx)
+
+		builder.AddDefault(func() { history = append(history, "default") })
+		require.Equal(t, 2, builder.CaseCount())
+		require.True(t, builder.HasDefault())
+		// the future is still ready, so it wins over the default case
+		builder.Build().Select(ctx)
+		NewSelectorBuilder(ctx).AddDefault(func() { history = append(history, "default") }).Build().Select(ctx)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+	require.EqualValues(t, []string{"receive-value", "future-value", "future-value", "default"}, history)
+}
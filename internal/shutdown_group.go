@@ -0,0 +1,87 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// ShutdownGroup runs background coroutines of a workflow and lets the workflow stop them and wait for them to exit
+	// before it completes. See NewShutdownGroup.
+	ShutdownGroup interface {
+		// Go starts f in a new coroutine that belongs to the group.
+		Go(ctx Context, f func(ctx Context))
+		// Done returns a Channel that is closed when the group is shutting down. Coroutines of the group receive
+		// from it, usually in a Selector, to observe the shutdown and exit.
+		Done() Channel
+		// Wait closes the Done channel and blocks until all the coroutines of the group exited. Go must not be
+		// called once Wait was called.
+		Wait(ctx Context)
+	}
+
+	shutdownGroupImpl struct {
+		done      Channel
+		waitGroup WaitGroup
+		shutdown  bool
+	}
+)
+
+// NewShutdownGroup creates a ShutdownGroup. It makes sure that background coroutines are done with their work, for
+// example with the activity they were waiting for, before the workflow function returns:
+//  group := workflow.NewShutdownGroup(ctx)
+//  group.Go(ctx, func(ctx workflow.Context) {
+//    for done := false; !done; {
+//      selector := workflow.NewSelector(ctx)
+//      selector.AddReceive(group.Done(), func(c workflow.Channel, more bool) { done = true })
+//      selector.AddReceive(requests, handleRequest)
+//      selector.Select(ctx)
+//    }
+//  })
+//  ...
+//  group.Wait(ctx)
+//  return nil
+func NewShutdownGroup(ctx Context) ShutdownGroup {
+	return &shutdownGroupImpl{
+		done:      NewNamedChannel(ctx, "shutdown"),
+		waitGroup: NewWaitGroup(ctx),
+	}
+}
+
+func (g *shutdownGroupImpl) Go(ctx Context, f func(ctx Context)) {
+	if g.shutdown {
+		panic("ShutdownGroup.Go called after Wait")
+	}
+	g.waitGroup.Add(1)
+	Go(ctx, func(ctx Context) {
+		defer g.waitGroup.Done()
+		f(ctx)
+	})
+}
+
+func (g *shutdownGroupImpl) Done() Channel {
+	return g.done
+}
+
+func (g *shutdownGroupImpl) Wait(ctx Context) {
+	if !g.shutdown {
+		g.shutdown = true
+		g.done.Close()
+	}
+	g.waitGroup.Wait(ctx)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testShutdownGroupWorkflow(ctx Context) ([]string, error) {
+	var events []string
+	requests := NewBufferedChannel(ctx, 10)
+	group := NewShutdownGroup(ctx)
+	for i := 0; i < 2; i++ {
+		name := fmt.Sprintf("worker-%v", i)
+		group.Go(ctx, func(ctx Context) {
+			for done := false; !done; {
+				selector := NewSelector(ctx)
+				selector.AddReceive(group.Done(), func(c Channel, more bool) { done = true })
+				selector.AddReceive(requests, func(c Channel, more bool) {
+					var request string
+					c.Receive(ctx, &request)
+					// work that is still in progress when the shutdown starts is completed
+					Sleep(ctx, time.Minute)
+					events = append(events, name+"-"+request)
+				})
+				selector.Select(ctx)
+			}
+			events = append(events, name+"-exited")
+		})
+	}
+	requests.SendAsync("a")
+	requests.SendAsync("b")
+	Sleep(ctx, time.Second)
+	group.Wait(ctx)
+	events = append(events, "wait-returned")
+	return events, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_ShutdownGroup() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testShutdownGroupWorkflow)
+	env.ExecuteWorkflow(testShutdownGroupWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var events []string
+	s.NoError(env.GetWorkflowResult(&events))
+	s.Equal([]string{
+		"worker-0-a", "worker-0-exited", "worker-1-b", "worker-1-exited", "wait-returned",
+	}, events)
+}
+
+func TestShutdownGroup_GoAfterWait(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		group := NewShutdownGroup(ctx)
+		group.Wait(ctx)
+		// Wait can be called again and does not block
+		group.Wait(ctx)
+		require.Panics(t, func() { group.Go(ctx, func(ctx Context) {}) })
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
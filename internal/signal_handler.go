@@ -0,0 +1,80 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+type (
+	// SignalHandlerOptions configures SetSignalHandler.
+	SignalHandlerOptions struct {
+		// MaxConcurrentHandlers - The maximum number of handler calls that run at the same time. While the limit is
+		// reached, further signals stay queued on the signal channel, in the order they were received, until a running
+		// handler returns.
+		// Optional: default 0, means no limit.
+		MaxConcurrentHandlers int
+	}
+)
+
+// SetSignalHandler calls handler in a new coroutine for every signal named signalName, with the signal as a Value to
+// decode the payload from. Signals are consumed from the channel returned by GetSignalChannel, so do not receive from
+// that channel elsewhere. No new handler is started once ctx is canceled, running handlers see the cancellation
+// through their ctx.
+//  workflow.SetSignalHandler(ctx, "order", func(ctx workflow.Context, signal encoded.Value) {
+//    var order Order
+//    signal.Get(&order)
+//    ...
+//  }, workflow.SignalHandlerOptions{MaxConcurrentHandlers: 2})
+// SetSignalHandler panics if MaxConcurrentHandlers is negative.
+func SetSignalHandler(ctx Context, signalName string, handler func(ctx Context, signal Value), options SignalHandlerOptions) {
+	if options.MaxConcurrentHandlers < 0 {
+		panic("negative MaxConcurrentHandlers for SetSignalHandler")
+	}
+	ch := GetSignalChannel(ctx, signalName)
+	dc := getDataConverterFromWorkflowContext(ctx)
+	running := 0
+	Go(ctx, func(ctx Context) {
+		for {
+			if options.MaxConcurrentHandlers > 0 {
+				if err := Await(ctx, func() bool { return running < options.MaxConcurrentHandlers }); err != nil {
+					return
+				}
+			}
+			var signal Value
+			selector := NewSelector(ctx).
+				AddReceive(ch, func(c Channel, more bool) {
+					v, _, _ := c.(*channelImpl).receiveAsyncImpl(nil)
+					payload, _ := v.([]byte)
+					signal = newEncodedValue(payload, dc)
+				})
+			if done := ctx.Done(); done != nil {
+				selector.AddReceive(done, func(c Channel, more bool) {})
+			}
+			selector.Select(ctx)
+			if signal == nil {
+				return
+			}
+			running++
+			Go(ctx, func(ctx Context) {
+				defer func() { running-- }()
+				handler(ctx, signal)
+			})
+		}
+	})
+}
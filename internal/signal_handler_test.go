@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type signalHandlerWorkflowResult struct {
+	Started       []int
+	MaxConcurrent int
+}
+
+func testSignalHandlerWorkflow(ctx Context, maxConcurrentHandlers, signalCount int) (signalHandlerWorkflowResult, error) {
+	var result signalHandlerWorkflowResult
+	running, done := 0, 0
+	SetSignalHandler(ctx, "test-signal", func(ctx Context, signal Value) {
+		var n int
+		if err := signal.Get(&n); err != nil {
+			panic(err)
+		}
+		result.Started = append(result.Started, n)
+		running++
+		if running > result.MaxConcurrent {
+			result.MaxConcurrent = running
+		}
+		_ = Sleep(ctx, time.Minute)
+		running--
+		done++
+	}, SignalHandlerOptions{MaxConcurrentHandlers: maxConcurrentHandlers})
+	err := Await(ctx, func() bool { return done == signalCount })
+	return result, err
+}
+
+func (s *WorkflowTestSuiteUnitTest) executeSignalHandlerWorkflow(maxConcurrentHandlers int) signalHandlerWorkflowResult {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testSignalHandlerWorkflow)
+	for i := 0; i < 6; i++ {
+		i := i
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow("test-signal", i)
+		}, time.Second)
+	}
+	env.ExecuteWorkflow(testSignalHandlerWorkflow, maxConcurrentHandlers, 6)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var result signalHandlerWorkflowResult
+	s.NoError(env.GetWorkflowResult(&result))
+	return result
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_SetSignalHandlerMaxConcurrentHandlers() {
+	result := s.executeSignalHandlerWorkflow(2)
+	s.Equal([]int{0, 1, 2, 3, 4, 5}, result.Started, "queued signals must be handled in order")
+	s.Equal(2, result.MaxConcurrent)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_SetSignalHandlerUnlimited() {
+	result := s.executeSignalHandlerWorkflow(0)
+	s.Equal([]int{0, 1, 2, 3, 4, 5}, result.Started)
+	s.Equal(6, result.MaxConcurrent)
+}
+
+func TestSetSignalHandler_NotCancellableContext(t *testing.T) {
+	var received []string
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		SetSignalHandler(ctx, "test-signal", func(ctx Context, signal Value) {
+			var v string
+			require.NoError(t, signal.Get(&v))
+			received = append(received, v)
+		}, SignalHandlerOptions{})
+		payload, err := encodeArg(nil, "one")
+		require.NoError(t, err)
+		GetSignalChannel(ctx, "test-signal").SendAsync(payload)
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.Equal(t, []string{"one"}, received)
+}
+
+func TestSetSignalHandler_NegativeLimit(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		require.Panics(t, func() {
+			SetSignalHandler(ctx, "test-signal", func(ctx Context, signal Value) {}, SignalHandlerOptions{MaxConcurrentHandlers: -1})
+		})
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
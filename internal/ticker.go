@@ -0,0 +1,83 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import "time"
+
+type (
+	// Ticker delivers ticks at a fixed interval of the workflow clock. See NewTicker.
+	Ticker interface {
+		// Next blocks until the next tick. It returns false without waiting for the tick when ctx, or the context
+		// the Ticker was created with, is canceled.
+		Next(ctx Context) bool
+	}
+
+	tickerImpl struct {
+		ctx      Context
+		interval time.Duration
+		next     time.Time
+	}
+)
+
+// NewTicker creates a Ticker that ticks every interval, starting one interval after its creation. It replaces the
+// hand written loop around NewTimer of polling workflows:
+//  ticker := workflow.NewTicker(ctx, time.Minute)
+//  for ticker.Next(ctx) {
+//    ...
+//  }
+// Ticks are scheduled relative to the creation time rather than to the previous call to Next, so the time spent
+// between two calls does not make the ticks drift. Like for time.Ticker, ticks that were missed because the workflow
+// was busy for longer than an interval are dropped, the following call to Next returns right away.
+// The waiting is implemented with workflow timers, so it is deterministic. As timers have a resolution of a second,
+// the interval should be a whole number of seconds. NewTicker panics if interval is not positive.
+func NewTicker(ctx Context, interval time.Duration) Ticker {
+	if interval <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return &tickerImpl{ctx: ctx, interval: interval, next: Now(ctx).Add(interval)}
+}
+
+func (t *tickerImpl) Next(ctx Context) bool {
+	if ctx.Err() != nil || t.ctx.Err() != nil {
+		return false
+	}
+	if wait := t.next.Sub(Now(ctx)); wait > 0 {
+		timerCtx, cancelTimer := WithCancel(ctx)
+		defer cancelTimer()
+		fired := false
+		selector := NewSelector(ctx)
+		selector.AddFuture(NewTimer(timerCtx, wait), func(f Future) {
+			fired = f.Get(timerCtx, nil) == nil
+		})
+		if done := t.ctx.Done(); done != nil {
+			selector.AddReceive(done, func(c Channel, more bool) {})
+		}
+		selector.Select(ctx)
+		if !fired {
+			return false
+		}
+	}
+	for now := Now(ctx); !t.next.After(now); {
+		t.next = t.next.Add(t.interval)
+	}
+	return true
+}
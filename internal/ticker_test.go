@@ -0,0 +1,96 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testTickerWorkflow(ctx Context) ([]time.Duration, error) {
+	start := Now(ctx)
+	ticker := NewTicker(ctx, 10*time.Second)
+	var offsets []time.Duration
+	for i := 0; i < 4; i++ {
+		if !ticker.Next(ctx) {
+			return nil, ctx.Err()
+		}
+		offsets = append(offsets, Now(ctx).Sub(start))
+		switch i {
+		case 0:
+			// work shorter than the interval does not delay the next tick
+			if err := Sleep(ctx, 3*time.Second); err != nil {
+				return nil, err
+			}
+		case 1:
+			// work longer than the interval drops the missed ticks
+			if err := Sleep(ctx, 25*time.Second); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return offsets, nil
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_TickerTicksWithoutDrift() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(testTickerWorkflow)
+	env.ExecuteWorkflow(testTickerWorkflow)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+
+	var offsets []time.Duration
+	s.NoError(env.GetWorkflowResult(&offsets))
+	s.Equal([]time.Duration{10 * time.Second, 20 * time.Second, 45 * time.Second, 50 * time.Second}, offsets)
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_TickerCanceled() {
+	workflowFn := func(ctx Context) (int, error) {
+		ctx, cancel := WithCancel(ctx)
+		ticker := NewTicker(ctx, time.Minute)
+		Go(ctx, func(ctx Context) {
+			Sleep(ctx, 150*time.Second)
+			cancel()
+		})
+		ticks := 0
+		// the pending Next is unblocked by the cancellation of the context the ticker was created with
+		disconnectedCtx, _ := NewDisconnectedContext(ctx)
+		for ticker.Next(disconnectedCtx) {
+			ticks++
+		}
+		return ticks, nil
+	}
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.ExecuteWorkflow(workflowFn)
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var ticks int
+	s.NoError(env.GetWorkflowResult(&ticks))
+	s.Equal(2, ticks)
+}
+
+func TestTicker_NonPositiveInterval(t *testing.T) {
+	require.Panics(t, func() { NewTicker(nil, 0) })
+}
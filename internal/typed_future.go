@@ -0,0 +1,129 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.21
+// +build go1.21
+
+// The module declares go 1.13, which does not allow type parameters. Go 1.21 and later build a file that has a
+// go1.21 build constraint at the language version of the constraint, so this file is left out by older toolchains
+// and built as Go 1.21 code by newer ones, without raising the go version of the module.
+
+package internal
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type (
+	// TypedFuture is a Future with a result of type T.
+	TypedFuture[T any] interface {
+		// Get blocks until the future is ready. It returns the result, or an error if the future failed or its value
+		// is not a T.
+		Get(ctx Context) (T, error)
+
+		// When true Get is guaranteed to not block
+		IsReady() bool
+
+		// Future returns the underlying untyped Future, for example to add it to a Selector.
+		Future() Future
+	}
+
+	// TypedSettable is used to set value or error on a TypedFuture.
+	TypedSettable[T any] interface {
+		Set(value T, err error)
+		SetValue(value T)
+		SetError(err error)
+		SetCancel()                  // Resolves the future with ErrCanceled.
+		Chain(future TypedFuture[T]) // Value (or error) of the future become the same of the chained one.
+	}
+
+	typedFutureImpl[T any] struct {
+		future asyncFuture
+	}
+
+	typedSettableImpl[T any] struct {
+		settable Settable
+	}
+)
+
+// NewTypedFuture creates a new TypedFuture as well as associated TypedSettable that is used to set its value.
+func NewTypedFuture[T any](ctx Context) (TypedFuture[T], TypedSettable[T]) {
+	f, s := NewFuture(ctx)
+	return &typedFutureImpl[T]{future: f.(asyncFuture)}, &typedSettableImpl[T]{settable: s}
+}
+
+// NewTypedFutureFrom wraps a Future, for example the one returned by ExecuteActivity, into a TypedFuture.
+func NewTypedFutureFrom[T any](future Future) TypedFuture[T] {
+	f, ok := future.(asyncFuture)
+	if !ok {
+		panic("cannot wrap Future that wasn't created with workflow.NewFuture")
+	}
+	return &typedFutureImpl[T]{future: f}
+}
+
+func (f *typedFutureImpl[T]) Get(ctx Context) (T, error) {
+	var result T
+	if err := f.future.Get(ctx, nil); err != nil {
+		return result, err
+	}
+	value, _ := f.future.GetValueAndError()
+	if value == nil {
+		return result, nil
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	// Futures of activities and child workflows hold the encoded result, let the future decode it.
+	if _, ok := value.([]byte); ok {
+		err := f.future.Get(ctx, &result)
+		return result, err
+	}
+	return result, fmt.Errorf("future value of type %T is not assignable to %v", value, reflect.TypeOf(&result).Elem())
+}
+
+func (f *typedFutureImpl[T]) IsReady() bool {
+	return f.future.IsReady()
+}
+
+func (f *typedFutureImpl[T]) Future() Future {
+	return f.future
+}
+
+func (s *typedSettableImpl[T]) Set(value T, err error) {
+	s.settable.Set(value, err)
+}
+
+func (s *typedSettableImpl[T]) SetValue(value T) {
+	s.settable.SetValue(value)
+}
+
+func (s *typedSettableImpl[T]) SetError(err error) {
+	s.settable.SetError(err)
+}
+
+func (s *typedSettableImpl[T]) SetCancel() {
+	s.settable.SetCancel()
+}
+
+func (s *typedSettableImpl[T]) Chain(future TypedFuture[T]) {
+	s.settable.Chain(future.Future())
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017-2020 Uber Technologies Inc.
+// Portions of the Software are attributed to Copyright (c) 2020 Temporal Technologies Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.21
+// +build go1.21
+
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedFuture(t *testing.T) {
+	d, _ := newDispatcher(createRootTestContext(), func(ctx Context) {
+		f, s := NewTypedFuture[string](ctx)
+		require.False(t, f.IsReady())
+		s.SetValue("value")
+		require.True(t, f.IsReady())
+		v, err := f.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "value", v)
+
+		failed, s2 := NewTypedFuture[int](ctx)
+		s2.SetError(errors.New("failed"))
+		_, err = failed.Get(ctx)
+		require.EqualError(t, err, "failed")
+
+		source, sourceSettable := NewTypedFuture[string](ctx)
+		chained, s3 := NewTypedFuture[string](ctx)
+		s3.Chain(source)
+		sourceSettable.SetValue("value")
+		v, err = chained.Get(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "value", v)
+
+		// value of the wrong type is reported as error instead of panic
+		untyped, untypedSettable := NewFuture(ctx)
+		untypedSettable.SetValue(42)
+		_, err = NewTypedFutureFrom[string](untyped).Get(ctx)
+		require.EqualError(t, err, "future value of type int is not assignable to string")
+	})
+	require.NoError(t, d.ExecuteUntilAllBlocked())
+	require.True(t, d.IsDone())
+}
+
+func (s *WorkflowTestSuiteUnitTest) Test_TypedFutureFromActivity() {
+	activityFn := func(arg int) (int, error) {
+		return arg * 2, nil
+	}
+	workflowFn := func(ctx Context) (int, error) {
+		ctx = WithActivityOptions(ctx, ActivityOptions{
+			ScheduleToStartTimeout: time.Minute,
+			StartToCloseTimeout:    time.Minute,
+		})
+		return NewTypedFutureFrom[int](ExecuteActivity(ctx, activityFn, 5)).Get(ctx)
+	}
+
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflowFn)
+	env.RegisterActivity(activityFn)
+
+	env.ExecuteWorkflow(workflowFn)
+	s.NoError(env.GetWorkflowError())
+	var out int
+	s.NoError(env.GetWorkflowResult(&out))
+	s.Equal(10, out)
+}
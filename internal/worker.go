@@ -199,6 +199,9 @@ type (
 		WorkflowInterceptorChainFactories []WorkflowInterceptorFactory
 
 		// Optional: Sets ContextPropagators that allows users to control the context information passed through a workflow
+		// The propagators run in the order of the slice, both when injecting headers for activities and child workflows
+		// and when extracting them, so a propagator can rely on the context produced by the ones before it. Use
+		// NewChainedPropagator to register a group of propagators as one.
 		// default: no ContextPropagators
 		ContextPropagators []ContextPropagator
 
@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -53,6 +54,8 @@ type (
 		// Parameter valuePtr is a pointer to the expected data structure to be received. For example:
 		//  var v string
 		//  c.Receive(ctx, &v)
+		// When several coroutines are blocked on Receive, values are delivered to them one at a time in the order in
+		// which they blocked, so work sent over a Channel is distributed evenly among its receivers.
 		Receive(ctx Context, valuePtr interface{}) (more bool)
 
 		// ReceiveAsync try to receive from Channel without blocking. If there is data available from the Channel, it
@@ -63,14 +66,33 @@ type (
 		// more value from the Channel. The more is false when Channel is closed.
 		ReceiveAsyncWithMoreFlag(valuePtr interface{}) (ok bool, more bool)
 
-		// Send blocks until the data is sent.
+		// ReceiveAll receives all the values that are available from the Channel without blocking and returns them in
+		// the order they were sent, or an empty slice when no value is available. It also returns the values left in a
+		// closed Channel. The values are returned as sent, so the values of a signal channel are encoded payloads.
+		ReceiveAll(ctx Context) []interface{}
+
+		// Send blocks until the data is sent. Like receivers, blocked senders are served in the order in which they
+		// blocked.
 		Send(ctx Context, v interface{})
 
+		// SendWithTimeout blocks until the data is sent or the timeout d elapses on the workflow clock, whichever
+		// happens first. It returns false if the data was not sent, which is also the case when ctx is canceled.
+		// Like Send, it panics if the Channel is closed.
+		SendWithTimeout(ctx Context, v interface{}, d time.Duration) (sent bool)
+
 		// SendAsync try to send without blocking. It returns true if the data was sent, otherwise it returns false.
 		SendAsync(v interface{}) (ok bool)
 
-		// Close close the Channel, and prohibit subsequent sends.
+		// Close close the Channel, and prohibit subsequent sends. Closing a Channel that is already closed is a no-op.
 		Close()
+
+		// CloseOnce closes the Channel like Close, and returns true if this call closed it or false if the Channel was
+		// already closed. It lets coroutines close a shared Channel without agreeing on which one owns it.
+		CloseOnce() (closed bool)
+
+		// Len returns the number of values currently buffered in the Channel without consuming them. Values of
+		// blocked Send calls are not counted, so it always returns 0 for an unbuffered Channel.
+		Len() int
 	}
 
 	// Selector must be used instead of native go select by workflow code.
@@ -79,8 +101,45 @@ type (
 		AddReceive(c Channel, f func(c Channel, more bool)) Selector
 		AddSend(c Channel, v interface{}, f func()) Selector
 		AddFuture(future Future, f func(f Future)) Selector
-		AddDefault(f func())
+		// AddDefault sets the default case, f is called by Select when none of the added cases is ready. A Selector
+		// has at most one default case, adding another one replaces it.
+		AddDefault(f func()) Selector
 		Select(ctx Context)
+
+		// SelectWithDefault is Select with f as the default case for this call only, in place of the one set by
+		// AddDefault, if any. It never blocks.
+		SelectWithDefault(ctx Context, f func())
+
+		// HasDefault returns true if a default case was set by AddDefault.
+		HasDefault() bool
+
+		// SelectIndex is Select that also returns the index of the case that was executed, counting the cases that are
+		// currently added in the order they were added, starting from 0. It returns -1 when the default case was
+		// executed or when Select was woken by Wake.
+		SelectIndex(ctx Context) int
+
+		// Wake makes the Select blocked on this Selector return without executing any of the added cases, the wake
+		// case set by AddWake is executed instead, if any. Use it from another coroutine to interrupt a wait that
+		// none of the cases would end. When no Select is blocked, the next one is woken instead, but a case that is
+		// ready or the default case take precedence. Several calls to Wake before Select returns wake it only once.
+		Wake()
+
+		// AddWake sets the wake case, f is called by Select when it is woken by Wake. A Selector has at most one wake
+		// case, adding another one replaces it.
+		AddWake(f func()) Selector
+
+		// RemoveReceive removes all receive cases that were added for the given Channel. Cases are matched by the
+		// identity of the Channel. It is a no-op if there is no such case.
+		RemoveReceive(c Channel) Selector
+
+		// RemoveFuture removes all cases that were added for the given Future. Cases are matched by the identity of
+		// the Future. It is a no-op if there is no such case.
+		RemoveFuture(future Future) Selector
+
+		// HasPending returns true if Select would not block, that is if any added receive case has a value available
+		// or a closed channel, any send case can complete, or any added future is ready. It does not execute any case
+		// function and does not take the default case into account.
+		HasPending() bool
 	}
 
 	// WaitGroup must be used instead of native go sync.WaitGroup by
@@ -92,6 +151,15 @@ type (
 		Wait(ctx Context)
 	}
 
+	// Mutex must be used instead of native go sync.Mutex by workflow code to protect state that is shared by
+	// several coroutines. Use workflow.NewMutex(ctx) method to create a new Mutex instance.
+	Mutex interface {
+		// Lock blocks the calling coroutine until the mutex is available.
+		Lock(ctx Context)
+		// Unlock releases the mutex. It panics if the mutex is not locked.
+		Unlock()
+	}
+
 	// Future represents the result of an asynchronous computation.
 	Future interface {
 		// Get blocks until the future is ready. When ready it either returns non nil error or assigns result value to
@@ -109,6 +177,20 @@ type (
 
 		// When true Get is guaranteed to not block
 		IsReady() bool
+
+		// GetWithTimeout blocks until the future is ready or the duration d elapsed, whichever happens first.
+		// When the future is ready it returns the value and the error the future was set with and timedOut is false.
+		// For futures returned by ExecuteActivity, ExecuteChildWorkflow etc. the value is still encoded, call Get
+		// afterwards to decode it; Get does not block at that point.
+		// When d elapsed first it returns a nil value and error and timedOut is true.
+		// If ctx is canceled while waiting, it returns *CanceledError and timedOut is false.
+		// The wait is backed by a workflow timer, so it is deterministic on replay.
+		// Example:
+		//  _, err, timedOut := f.GetWithTimeout(ctx, time.Minute)
+		//  if timedOut {
+		//      // give up on the future
+		//  }
+		GetWithTimeout(ctx Context, d time.Duration) (value interface{}, err error, timedOut bool)
 	}
 
 	// Settable is used to set value or error on a future.
@@ -117,7 +199,13 @@ type (
 		Set(value interface{}, err error)
 		SetValue(value interface{})
 		SetError(err error)
+		SetCancel()          // Resolves the future with ErrCanceled, the same error a canceled activity or timer returns.
 		Chain(future Future) // Value (or error) of the future become the same of the chained one.
+		// ChainWithTransform is like Chain, but the value of the chained future is passed through transform before it
+		// is set, and an error returned by transform is set instead. An error of the chained future is set as is,
+		// without calling transform. Encoded results, like the ones of ExecuteActivity, are passed to transform as
+		// []byte. Like a query handler, transform must not call any workflow blocking functions.
+		ChainWithTransform(future Future, transform func(v interface{}) (interface{}, error))
 	}
 
 	// ChildWorkflowFuture represents the result of a child workflow execution
@@ -156,6 +244,65 @@ type (
 	// Version represents a change version. See GetVersion call.
 	Version int
 
+	// SignalChannelOptions stores options for a signal channel. See GetSignalChannelWithOptions call.
+	SignalChannelOptions struct {
+		// MaxPayloadSize - The maximum size in bytes of the encoded signal payload. Signals with a larger payload are
+		// not delivered to the channel and are passed to OversizeSignalHandler instead.
+		// Optional: default 0, means no limit.
+		MaxPayloadSize int
+
+		// OversizeSignalHandler - Called with the signal name and the encoded payload of every signal that exceeds
+		// MaxPayloadSize. The handler is invoked out of the context of the workflow, so like a query handler it must
+		// not call any workflow blocking functions. It is called on replay as well, so it has to be deterministic.
+		// Optional: default nil, means oversize signals are dropped.
+		OversizeSignalHandler func(signalName string, payload []byte)
+
+		// MaxBufferedSignals - The maximum number of signals buffered in the channel waiting to be received. Signals
+		// that arrive while the channel is full are not buffered and are only counted, see GetDroppedSignalCount.
+		// Optional: default 0, means the default signal channel buffer size.
+		MaxBufferedSignals int
+	}
+
+	// ExecuteActivityParameters describes a single activity invocation of ExecuteActivitiesAsync.
+	ExecuteActivityParameters struct {
+		// Activity - Either an activity name (string) or a function representing an activity.
+		Activity interface{}
+
+		// Args - The arguments that need to be passed to the activity.
+		Args []interface{}
+
+		// Options - The options to schedule the activity with.
+		// Optional: default nil, means the activity options of the context are used.
+		Options *ActivityOptions
+
+		// RetryPolicy - The policy the Cadence server uses to retry the activity, it overrides the retry policy of
+		// Options or of the context. Failures whose CustomError reason is listed in NonRetriableErrorReasons are not
+		// retried. The future of the activity is only resolved with the result of the last attempt.
+		// Optional: default nil, means the retry policy of Options or of the context is used.
+		RetryPolicy *RetryPolicy
+
+		// CancellationGracePeriodSeconds - When WaitForCancellation is set, bounds how long the workflow waits for the
+		// activity to acknowledge a cancellation. Once the grace period elapsed the future of the activity is resolved
+		// with CanceledError, even if the activity is still running. When positive it overrides the
+		// CancellationGracePeriod of Options or of the context.
+		// Optional: default 0, means the CancellationGracePeriod of Options or of the context is used.
+		CancellationGracePeriodSeconds int32
+	}
+
+	// TimerOptions stores callbacks for a timer. See NewTimerWithOptions call.
+	TimerOptions struct {
+		// OnFire - Called when the timer fires, before the timer future becomes ready. It runs as part of the workflow
+		// execution and is called on replay as well, so it has to be deterministic and must not call any workflow
+		// blocking functions.
+		// Optional: default nil.
+		OnFire func()
+
+		// OnCancel - Called when the timer is canceled, before the timer future becomes ready. The same restrictions
+		// as for OnFire apply.
+		// Optional: default nil.
+		OnCancel func()
+	}
+
 	// ChildWorkflowOptions stores all child workflow specific parameters that will be stored inside of a Context.
 	// The current timeout resolution implementation is in seconds and uses math.Ceil(d.Seconds()) as the duration. But is
 	// subjected to change in the future.
@@ -317,6 +464,48 @@ func NewNamedBufferedChannel(ctx Context, name string, size int) Channel {
 	return &channelImpl{name: name, size: size, dataConverter: getDataConverterFromWorkflowContext(ctx), env: env}
 }
 
+// Drain receives all the values that can be received from the channel without blocking and returns them in the
+// order they were sent. It does not wait for the channel to be closed, so for an open channel it returns whatever is
+// buffered at the moment of the call. Values sent by a workflow are returned as is, while the values of signal
+// channels are the encoded signal payloads ([]byte). It is Channel.ReceiveAll, except that it returns nil instead of
+// an empty slice when there is nothing to receive.
+func Drain(ch Channel) []interface{} {
+	values := ch.ReceiveAll(nil)
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// CollectUniqueN receives values from c until it collected n values with distinct keys, and returns them in the order
+// they were received. keyOf returns the key of a value, values whose key was already seen are dropped. Like Drain, it
+// passes the values of signal channels to keyOf and returns them as the encoded signal payloads ([]byte).
+// It returns fewer than n values if c is closed or ctx is canceled before enough unique values arrived.
+func CollectUniqueN(ctx Context, c Channel, n int, keyOf func(interface{}) string) []interface{} {
+	ch := c.(*channelImpl)
+	var values []interface{}
+	seen := make(map[string]bool)
+	for len(values) < n {
+		var v interface{}
+		received := false
+		selector := NewSelector(ctx).AddReceive(ch, func(c Channel, more bool) {
+			v, received, _ = ch.receiveAsyncImpl(nil)
+		})
+		if done := ctx.Done(); done != nil {
+			selector.AddReceive(done, func(c Channel, more bool) {})
+		}
+		selector.Select(ctx)
+		if !received {
+			return values
+		}
+		if key := keyOf(v); !seen[key] {
+			seen[key] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 // NewSelector creates a new Selector instance.
 func NewSelector(ctx Context) Selector {
 	state := getState(ctx)
@@ -336,6 +525,11 @@ func NewWaitGroup(ctx Context) WaitGroup {
 	return &waitGroupImpl{future: f, settable: s}
 }
 
+// NewMutex creates a new Mutex instance.
+func NewMutex(ctx Context) Mutex {
+	return &mutexImpl{channel: NewBufferedChannel(ctx, 1)}
+}
+
 // Go creates a new coroutine. It has similar semantic to goroutine in a context of the workflow.
 func Go(ctx Context, f func(ctx Context)) {
 	state := getState(ctx)
@@ -356,6 +550,13 @@ func NewFuture(ctx Context) (Future, Settable) {
 	return impl, impl
 }
 
+// NewNamedFuture creates a new future with a given human readable name as well as associated Settable.
+// Name appears in stack traces of coroutines that are blocked on this Future.
+func NewNamedFuture(ctx Context, name string) (Future, Settable) {
+	impl := &futureImpl{channel: NewNamedChannel(ctx, name).(*channelImpl)}
+	return impl, impl
+}
+
 func (wc *workflowEnvironmentInterceptor) ExecuteWorkflow(ctx Context, workflowType string, inputArgs ...interface{}) (results []interface{}) {
 	args := []reflect.Value{reflect.ValueOf(ctx)}
 	for _, arg := range inputArgs {
@@ -455,21 +656,55 @@ func (wc *workflowEnvironmentInterceptor) ExecuteActivity(ctx Context, typeName
 		DataConverter:   dataConverter,
 		Header:          header,
 	}
+	// Guard against runaway activity scheduling.
+	if envOptions := getWorkflowEnvOptions(ctx); envOptions != nil &&
+		envOptions.activityLimit > 0 && wc.scheduledActivities >= envOptions.activityLimit {
+		settable.Set(nil, ErrActivityLimitExceeded)
+		return future
+	}
+	wc.scheduledActivities++
 
 	ctxDone, cancellable := ctx.Done().(*channelImpl)
 	cancellationCallback := &receiveCallback{}
-	a := getWorkflowEnvironment(ctx).ExecuteActivity(params, func(r []byte, e error) {
+	var gracePeriodTimer *timerInfo
+	resolve := func(r []byte, e error) {
+		if future.IsReady() {
+			// already resolved when the cancellation grace period elapsed
+			return
+		}
+		if params.OnComplete != nil {
+			params.OnComplete(r, e)
+		}
 		settable.Set(r, e)
 		if cancellable {
 			// future is done, we don't need the cancellation callback anymore.
 			ctxDone.removeReceiveCallback(cancellationCallback)
 		}
+	}
+	a := getWorkflowEnvironment(ctx).ExecuteActivity(params, func(r []byte, e error) {
+		resolve(r, e)
+		if gracePeriodTimer != nil {
+			// activity acknowledged the cancellation in time, the grace period timer is not needed anymore.
+			timerID := gracePeriodTimer.timerID
+			gracePeriodTimer = nil
+			wc.env.RequestCancelTimer(timerID)
+		}
 	})
 
 	if cancellable {
 		cancellationCallback.fn = func(v interface{}, more bool) bool {
-			if ctx.Err() == ErrCanceled {
+			if ctx.Err() != nil {
 				wc.env.RequestCancelActivity(a.activityID)
+				if params.WaitForCancellation && params.CancellationGracePeriodSeconds > 0 && !future.IsReady() {
+					// race the cancellation acknowledgment of the activity against the grace period.
+					d := time.Duration(params.CancellationGracePeriodSeconds) * time.Second
+					gracePeriodTimer = wc.env.NewTimer(d, func(r []byte, e error) {
+						if gracePeriodTimer != nil && e == nil {
+							gracePeriodTimer = nil
+							resolve(nil, NewCanceledError())
+						}
+					})
+				}
 			}
 			return false
 		}
@@ -481,6 +716,339 @@ func (wc *workflowEnvironmentInterceptor) ExecuteActivity(ctx Context, typeName
 	return future
 }
 
+// ExecuteActivityWithRetry requests activity execution like ExecuteActivity, retrying failed attempts from within the
+// workflow according to the given retry policy. Attempts that fail with CustomError, GenericError, PanicError or
+// TimeoutError are retried after a backoff computed from the policy, using a deterministic workflow timer between
+// attempts. Retry stops when the error reason is listed in NonRetriableErrorReasons, when MaximumAttempts is reached or
+// when ExpirationInterval has elapsed, in which case the future is resolved with the last attempt's error.
+//
+// Unlike RetryPolicy on ActivityOptions, which is handled by the Cadence server, each attempt is recorded as a separate
+// activity in the workflow history.
+// Canceling the context (workflow.WithCancel(ctx)) aborts the retry loop and resolves the future with CanceledError.
+// Retries also stop once the RetryBudget of the context, if any, is exhausted, see WithRetryBudget.
+//
+// RetryActivity is the blocking form that takes the activity as ExecuteActivityParameters and returns the encoded
+// result:
+//  result, err := workflow.RetryActivity(ctx, workflow.ExecuteActivityParameters{Activity: MyActivity}, retryPolicy)
+//
+// ExecuteActivityWithRetry returns Future with activity result or failure.
+func ExecuteActivityWithRetry(ctx Context, retryPolicy RetryPolicy, activity interface{}, args ...interface{}) Future {
+	registry := getRegistryFromWorkflowContext(ctx)
+	future, settable := newDecodeFuture(ctx, getActivityFunctionName(registry, activity))
+	if err := validateRetryPolicy(convertRetryPolicy(&retryPolicy)); err != nil {
+		settable.Set(nil, err)
+		return future
+	}
+	if retryPolicy.MaximumInterval == 0 {
+		// keep the default in sync with validateRetryPolicy
+		retryPolicy.MaximumInterval = 100 * retryPolicy.InitialInterval
+	}
+
+	dataConverter := getDataConverterFromWorkflowContext(ctx)
+	var expireTime time.Time
+	if retryPolicy.ExpirationInterval > 0 {
+		expireTime = Now(ctx).Add(retryPolicy.ExpirationInterval)
+	}
+	Go(ctx, func(ctx Context) {
+		for attempt := int32(0); ; attempt++ {
+			f := ExecuteActivity(ctx, activity, args...)
+			result, err := getFutureValueAndError(ctx, f)
+			if err == nil || !isRetryableActivityError(err) || ctx.Err() != nil {
+				settable.Set(result, err)
+				return
+			}
+
+			reason, _ := getErrorDetails(err, dataConverter)
+			backoff := getRetryBackoffWithNowTime(&retryPolicy, attempt, reason, Now(ctx), expireTime)
+			if backoff == noRetryBackoff {
+				settable.Set(result, err)
+				return
+			}
+			if budget := getRetryBudget(ctx); budget != nil && !budget.TryConsume() {
+				settable.Set(result, err)
+				return
+			}
+			if err := Sleep(ctx, backoff); err != nil {
+				settable.Set(nil, err)
+				return
+			}
+		}
+	})
+	return future
+}
+
+// isRetryableActivityError returns true for the activity failures ExecuteActivityWithRetry may retry.
+func isRetryableActivityError(err error) bool {
+	switch err.(type) {
+	case *CustomError, *GenericError, *PanicError, *TimeoutError:
+		return true
+	default:
+		return false
+	}
+}
+
+// BackoffUntilDeadline calls attempt until it reports done, backing off between the calls according to policy, but
+// gives up once the workflow clock would pass deadline. The backoffs are deterministic workflow timers:
+//  err := workflow.BackoffUntilDeadline(ctx, policy, workflow.Now(ctx).Add(time.Hour), func() (bool, error) {
+//    var status string
+//    err := workflow.ExecuteActivity(ctx, checkStatus).Get(ctx, &status)
+//    return status == "ready", err
+//  })
+// It returns nil as soon as attempt returns true, and the error of attempt as soon as it returns one. It returns a
+// TimeoutError of type ScheduleToClose when the next attempt would start after deadline, after ExpirationInterval of
+// policy or when MaximumAttempts attempts were made. The other fields of policy are used as with
+// ExecuteActivityWithRetry, MaximumAttempts and ExpirationInterval are optional as deadline already bounds the attempts.
+func BackoffUntilDeadline(ctx Context, policy RetryPolicy, deadline time.Time, attempt func() (bool, error)) error {
+	start := Now(ctx)
+	if !start.Before(deadline) {
+		return NewTimeoutError(s.TimeoutTypeScheduleToClose)
+	}
+	expireTime := deadline
+	if policy.ExpirationInterval > 0 && start.Add(policy.ExpirationInterval).Before(deadline) {
+		expireTime = start.Add(policy.ExpirationInterval)
+	}
+	policy.ExpirationInterval = expireTime.Sub(start)
+	if err := validateRetryPolicy(convertRetryPolicy(&policy)); err != nil {
+		return err
+	}
+	if policy.MaximumInterval == 0 {
+		// keep the default in sync with validateRetryPolicy
+		policy.MaximumInterval = 100 * policy.InitialInterval
+	}
+
+	for n := int32(0); ; n++ {
+		done, err := attempt()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		backoff := getRetryBackoffWithNowTime(&policy, n, "", Now(ctx), expireTime)
+		if backoff == noRetryBackoff {
+			return NewTimeoutError(s.TimeoutTypeScheduleToClose)
+		}
+		if err := Sleep(ctx, backoff); err != nil {
+			return err
+		}
+	}
+}
+
+// ExecuteActivitiesAsync requests execution of a batch of activities, in the order of params, and returns their
+// futures positionally. Use AwaitAll to wait for all of them:
+//  futures := workflow.ExecuteActivitiesAsync(ctx, []workflow.ExecuteActivityParameters{
+//    {Activity: activityA, Args: []interface{}{"a"}},
+//    {Activity: activityB, Args: []interface{}{"b"}},
+//  })
+//  _, errs := workflow.AwaitAll(ctx, futures)
+// Activities whose parameters fail ExecuteActivityParameters.Validate are not scheduled, their futures fail with the
+// error of Validate.
+func ExecuteActivitiesAsync(ctx Context, params []ExecuteActivityParameters) []Future {
+	futures := make([]Future, len(params))
+	for i, p := range params {
+		if err := p.Validate(ctx); err != nil {
+			future, settable := NewFuture(ctx)
+			settable.SetError(err)
+			futures[i] = future
+			continue
+		}
+		futures[i] = ExecuteActivity(p.activityContext(ctx), p.Activity, p.Args...)
+	}
+	return futures
+}
+
+// activityContext returns ctx with the options, retry policy and cancellation grace period of p applied.
+func (p ExecuteActivityParameters) activityContext(ctx Context) Context {
+	if p.Options != nil {
+		ctx = WithActivityOptions(ctx, *p.Options)
+	}
+	if p.RetryPolicy != nil {
+		ctx = WithRetryPolicy(ctx, *p.RetryPolicy)
+	}
+	if p.CancellationGracePeriodSeconds > 0 {
+		ctx = withCancellationGracePeriod(ctx, p.CancellationGracePeriodSeconds)
+	}
+	return ctx
+}
+
+// Validate checks the parameters of the activity that the server would otherwise only reject once the activity is
+// scheduled: the activity type and the task list must be set, at least one of ScheduleToCloseTimeout and
+// StartToCloseTimeout must be positive and HeartbeatTimeout must not be larger than StartToCloseTimeout. When Options
+// is nil the activity options of ctx are checked, like the activity would be scheduled with them. The returned error
+// wraps ErrInvalidActivityParameters.
+// ExecuteActivitiesAsync and ExecuteActivities call Validate and fail the future of an invalid activity without
+// scheduling it. Call it directly to check the parameters before passing them to another API.
+func (p ExecuteActivityParameters) Validate(ctx Context) error {
+	if name, ok := p.Activity.(string); p.Activity == nil || ok && name == "" {
+		return fmt.Errorf("%w: missing ActivityType", ErrInvalidActivityParameters)
+	}
+	options := getActivityOptions(p.activityContext(ctx))
+	if options == nil {
+		return fmt.Errorf("%w: missing activity options", ErrInvalidActivityParameters)
+	}
+	if options.TaskListName == "" && options.OriginalTaskListName == "" {
+		return fmt.Errorf("%w: missing TaskListName", ErrInvalidActivityParameters)
+	}
+	if options.ScheduleToCloseTimeoutSeconds <= 0 && options.StartToCloseTimeoutSeconds <= 0 {
+		return fmt.Errorf("%w: at least one of ScheduleToCloseTimeout and StartToCloseTimeout must be positive",
+			ErrInvalidActivityParameters)
+	}
+	if options.StartToCloseTimeoutSeconds > 0 && options.HeartbeatTimeoutSeconds > options.StartToCloseTimeoutSeconds {
+		return fmt.Errorf("%w: HeartbeatTimeout %v is larger than StartToCloseTimeout %v", ErrInvalidActivityParameters,
+			time.Duration(options.HeartbeatTimeoutSeconds)*time.Second,
+			time.Duration(options.StartToCloseTimeoutSeconds)*time.Second)
+	}
+	return nil
+}
+
+// AwaitAll blocks until all the futures are ready, and returns their values and errors positionally, regardless of
+// the order in which the futures became ready. Encoded results, like the ones of ExecuteActivity, are returned as
+// []byte, call Get on the corresponding future to decode them as it no longer blocks. Only the errors of futures that
+// were not created by the workflow package, like mocks, are returned, their values are nil.
+func AwaitAll(ctx Context, futures []Future) ([]interface{}, []error) {
+	values := make([]interface{}, len(futures))
+	errs := make([]error, len(futures))
+	for i, f := range futures {
+		values[i], errs[i] = getFutureValueAndError(ctx, f)
+	}
+	return values, errs
+}
+
+// getFutureValueAndError blocks until the future is ready and returns its raw value and error. Futures that were not
+// created by the workflow package don't expose their raw value, so only their error is returned.
+func getFutureValueAndError(ctx Context, f Future) (interface{}, error) {
+	err := f.Get(ctx, nil)
+	if af, ok := f.(asyncFuture); ok {
+		return af.GetValueAndError()
+	}
+	return nil, err
+}
+
+// ExecuteActivities executes a batch of activities, in the order of params, and blocks until all of them completed.
+// The encoded results and errors are returned positionally, results[i] and errs[i] belong to params[i]. Decode a
+// result with the data converter of the context, or use ExecuteActivitiesAsync to decode the results with Get.
+// Canceling ctx cancels all the activities that are still outstanding, their errors are then CanceledError.
+func ExecuteActivities(ctx Context, params []ExecuteActivityParameters) (results [][]byte, errs []error) {
+	values, errs := AwaitAll(ctx, ExecuteActivitiesAsync(ctx, params))
+	results = make([][]byte, len(values))
+	for i, v := range values {
+		results[i], _ = v.([]byte)
+	}
+	return results, errs
+}
+
+// RetryActivity executes the activity described by params and blocks until it completed, retrying failed attempts from
+// within the workflow according to policy, like ExecuteActivityWithRetry. It returns the encoded result of the first
+// successful attempt. Retry stops on a non retryable error, when MaximumAttempts is reached, when ExpirationInterval has
+// elapsed or when ctx is canceled; the error of the last attempt, or CanceledError, is then returned.
+// Backoff between attempts uses workflow timers, so the retries are replayed deterministically.
+func RetryActivity(ctx Context, params ExecuteActivityParameters, policy RetryPolicy) ([]byte, error) {
+	ctx = params.activityContext(ctx)
+	f := ExecuteActivityWithRetry(ctx, policy, params.Activity, params.Args...)
+	value, err := getFutureValueAndError(ctx, f)
+	result, _ := value.([]byte)
+	return result, err
+}
+
+// CallActivity executes the activity named activityType with input, which is used as is, and blocks until it
+// completed. It returns the encoded result of the activity. input has to be encoded with the data converter the
+// activity worker uses, like an ExecuteActivity argument would be.
+// The activity is scheduled with the activity options of ctx, so set the defaults once with WithActivityOptions and
+// override them for a single call with the other modifiers, which win over the defaults:
+//  ctx = workflow.WithActivityOptions(ctx, defaultOptions)
+//  result, err := workflow.CallActivity(workflow.WithStartToCloseTimeout(ctx, time.Hour), "slowActivity", input)
+func CallActivity(ctx Context, activityType string, input []byte) ([]byte, error) {
+	ctx = WithDataConverter(ctx, &rawInputDataConverter{getDataConverterFromWorkflowContext(ctx)})
+	f := ExecuteActivity(ctx, activityType, input)
+	value, err := getFutureValueAndError(ctx, f)
+	result, _ := value.([]byte)
+	return result, err
+}
+
+// rawInputDataConverter passes a single []byte value through without encoding it, see CallActivity.
+type rawInputDataConverter struct {
+	DataConverter
+}
+
+func (dc *rawInputDataConverter) ToData(value ...interface{}) ([]byte, error) {
+	if len(value) == 1 {
+		if input, ok := value[0].([]byte); ok {
+			return input, nil
+		}
+	}
+	return dc.DataConverter.ToData(value...)
+}
+
+// AwaitAny blocks until the first of the futures becomes ready, and returns its position in futures along with its
+// value and error. When several futures are already ready, the one with the lowest index is returned. Encoded results,
+// like the ones of ExecuteActivity, are returned as []byte, call Get on futures[index] to decode them.
+// If ctx is canceled before any future is ready, AwaitAny returns -1 and ErrCanceled. It also returns -1 right away
+// when futures is empty. Like Selector.AddFuture, it panics on futures that were not created by the workflow package.
+func AwaitAny(ctx Context, futures []Future) (index int, value interface{}, err error) {
+	index = -1
+	if len(futures) == 0 {
+		return index, nil, nil
+	}
+	selector := NewSelector(ctx)
+	for i, f := range futures {
+		if _, ok := f.(asyncFuture); !ok {
+			panic(fmt.Sprintf("cannot await Future at index %v that wasn't created with workflow.NewFuture", i))
+		}
+		i := i
+		selector.AddFuture(f, func(f Future) {
+			index = i
+			value, err = getFutureValueAndError(ctx, f)
+		})
+	}
+	if cancelCh := ctx.Done(); cancelCh != nil {
+		selector.AddReceive(cancelCh, func(c Channel, more bool) {
+			err = ctx.Err()
+		})
+	}
+	selector.Select(ctx)
+	return index, value, err
+}
+
+// MergeSortedResults waits for all the futures, each resolving to a chunk of values sorted according to less, and
+// merges the chunks into a single sorted slice. Each chunk is retrieved with Get into a []interface{}, so encoded
+// results, like the ones of ExecuteActivity, are decoded with the data converter of the future. Values that are equal
+// according to less keep the order of their futures, so the merge is deterministic. It returns the error of the first
+// future, in the order of futures, that failed:
+//  var futures []workflow.Future
+//  for _, shard := range shards {
+//    futures = append(futures, workflow.ExecuteActivity(ctx, sortShard, shard))
+//  }
+//  sorted, err := workflow.MergeSortedResults(ctx, futures, func(a, b interface{}) bool {
+//    return a.(string) < b.(string)
+//  })
+func MergeSortedResults(ctx Context, futures []Future, less func(a, b interface{}) bool) ([]interface{}, error) {
+	chunks := make([][]interface{}, len(futures))
+	var firstErr error
+	total := 0
+	for i, f := range futures {
+		if err := f.Get(ctx, &chunks[i]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		total += len(chunks[i])
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	result := make([]interface{}, 0, total)
+	for len(result) < total {
+		next := -1
+		for i, chunk := range chunks {
+			if len(chunk) > 0 && (next < 0 || less(chunk[0], chunks[next][0])) {
+				next = i
+			}
+		}
+		result = append(result, chunks[next][0])
+		chunks[next] = chunks[next][1:]
+	}
+	return result, nil
+}
+
 // ExecuteLocalActivity requests to run a local activity. A local activity is like a regular activity with some key
 // differences:
 // * Local activity is scheduled and run by the workflow worker locally.
@@ -601,7 +1169,7 @@ func (wc *workflowEnvironmentInterceptor) scheduleLocalActivity(ctx Context, par
 
 	if cancellable {
 		cancellationCallback.fn = func(v interface{}, more bool) bool {
-			if ctx.Err() == ErrCanceled {
+			if ctx.Err() != nil {
 				getWorkflowEnvironment(ctx).RequestCancelLocalActivity(la.activityID)
 			}
 			return false
@@ -647,6 +1215,13 @@ func (wc *workflowEnvironmentInterceptor) ExecuteChildWorkflow(ctx Context, chil
 	}
 	workflowOptionsFromCtx := getWorkflowEnvOptions(ctx)
 	dc := workflowOptionsFromCtx.dataConverter
+	if domain := workflowOptionsFromCtx.domain; domain != nil && *domain != GetWorkflowInfo(ctx).Domain {
+		// the child runs on the workers of another domain, encode its arguments and decode its result their way
+		if domainDC := getDomainDataConverter(*domain); domainDC != nil {
+			dc = domainDC
+			result.dataConverter = domainDC
+		}
+	}
 	env := getWorkflowEnvironment(ctx)
 	wfType, input, err := getValidatedWorkflowFunction(childWorkflowType, args, dc, env.GetRegistry())
 	if err != nil {
@@ -698,7 +1273,7 @@ func (wc *workflowEnvironmentInterceptor) ExecuteChildWorkflow(ctx Context, chil
 
 	if cancellable {
 		cancellationCallback.fn = func(v interface{}, more bool) bool {
-			if ctx.Err() == ErrCanceled && childWorkflowExecution != nil && !mainFuture.IsReady() {
+			if ctx.Err() != nil && childWorkflowExecution != nil && !mainFuture.IsReady() {
 				// child workflow started, and ctx cancelled
 				getWorkflowEnvironment(ctx).RequestCancelChildWorkflow(*options.domain, childWorkflowExecution.ID)
 			}
@@ -742,8 +1317,20 @@ type WorkflowInfo struct {
 	SearchAttributes                    *s.SearchAttributes // Value can be decoded using DefaultDataConverter.
 	BinaryChecksum                      *string
 	RetryPolicy                         *s.RetryPolicy
+	WorkflowStartTime                   time.Time    // Time of the WorkflowExecutionStarted event, stable across replay.
+	taskListKind                        TaskListKind // kind of the task list the current decision task was polled from
 }
 
+// TaskListKind is the kind of the task list a decision task was polled from. See GetCurrentTaskListKind call.
+type TaskListKind int
+
+const (
+	// TaskListKindNormal is the task list the workflow was started on.
+	TaskListKindNormal TaskListKind = iota
+	// TaskListKindSticky is the task list of the worker that has the workflow execution cached.
+	TaskListKindSticky
+)
+
 // GetBinaryChecksum returns the binary checksum(identifier) of this worker
 func (wInfo *WorkflowInfo) GetBinaryChecksum() string {
 	if wInfo.BinaryChecksum == nil {
@@ -762,6 +1349,14 @@ func (wc *workflowEnvironmentInterceptor) GetWorkflowInfo(ctx Context) *Workflow
 	return wc.env.WorkflowInfo()
 }
 
+// GetCurrentTaskListKind returns whether the current decision task was polled from the sticky task list of the worker
+// or from the normal task list of the workflow. It is meant for diagnosing sticky execution, for example to log or emit
+// metrics about sticky cache misses. The value depends on how the worker received the decision task and is not part of
+// the workflow history, so it must not be used to make decisions in the workflow code.
+func GetCurrentTaskListKind(ctx Context) TaskListKind {
+	return GetWorkflowInfo(ctx).taskListKind
+}
+
 // GetLogger returns a logger to be used in workflow's context
 func GetLogger(ctx Context) *zap.Logger {
 	i := getWorkflowInterceptor(ctx)
@@ -836,6 +1431,56 @@ func (wc *workflowEnvironmentInterceptor) NewTimer(ctx Context, d time.Duration)
 	return future
 }
 
+// NewTimerWithOptions returns a timer future like NewTimer, and invokes the callbacks of the options when the timer
+// fires or gets canceled. Use it to instrument timers, for example to emit a metric every time a timer fires:
+//  timer := workflow.NewTimerWithOptions(ctx, time.Hour, workflow.TimerOptions{
+//    OnFire: func() {
+//      workflow.GetMetricsScope(ctx).Counter("timer-fired").Inc(1)
+//    },
+//  })
+func NewTimerWithOptions(ctx Context, d time.Duration, options TimerOptions) Future {
+	future := NewTimer(ctx, d)
+	if options.OnFire == nil && options.OnCancel == nil {
+		return future
+	}
+
+	onDone := func(err error) {
+		if err == nil {
+			if options.OnFire != nil {
+				options.OnFire()
+			}
+		} else if _, ok := err.(*CanceledError); ok && options.OnCancel != nil {
+			options.OnCancel()
+		}
+	}
+	callback := &receiveCallback{fn: func(v interface{}, more bool) bool {
+		_, err := future.(asyncFuture).GetValueAndError()
+		onDone(err)
+		return false
+	}}
+	if _, ok, err := future.(asyncFuture).GetAsync(callback); ok {
+		// timer with a non positive duration is ready right away
+		onDone(err)
+	}
+	return future
+}
+
+// NewTimerWithDeadline returns a timer future like NewTimer, along with the time at which the timer is scheduled to
+// fire according to the workflow clock, that is Now(ctx).Add(d). Use the deadline for logging or to compute the time
+// remaining before the timer fires instead of relying on time.Now(), which is not replay safe:
+//  timer, deadline := workflow.NewTimerWithDeadline(ctx, time.Hour)
+//  ...
+//  remaining := deadline.Sub(workflow.Now(ctx))
+// A non positive duration returns the current workflow time as deadline.
+func NewTimerWithDeadline(ctx Context, d time.Duration) (Future, time.Time) {
+	now := Now(ctx)
+	deadline := now
+	if d > 0 {
+		deadline = now.Add(d)
+	}
+	return NewTimer(ctx, d), deadline
+}
+
 // Sleep pauses the current workflow for at least the duration d. A negative or zero duration causes Sleep to return
 // immediately. Workflow code needs to use this Sleep() to sleep instead of the Go lang library one(timer.Sleep()).
 // You can cancel the pending sleep by cancel the Context (using context from workflow.WithCancel(ctx)).
@@ -1053,6 +1698,16 @@ func WithWorkflowTaskStartToCloseTimeout(ctx Context, d time.Duration) Context {
 	return ctx1
 }
 
+// WithActivityLimit adds to the copy of the context a limit on the total number of activities the workflow run may
+// schedule. Once the limit is reached, ExecuteActivity returns a future that fails with ErrActivityLimitExceeded.
+// All activities scheduled by the workflow run count towards the limit, while the limit is only enforced for
+// ExecuteActivity calls made with the returned context. A limit of 0 means no limit.
+func WithActivityLimit(ctx Context, limit int) Context {
+	ctx1 := setWorkflowEnvOptionsIfNotExist(ctx)
+	getWorkflowEnvOptions(ctx1).activityLimit = limit
+	return ctx1
+}
+
 // WithDataConverter adds DataConverter to the context.
 func WithDataConverter(ctx Context, dc DataConverter) Context {
 	if dc == nil {
@@ -1080,6 +1735,132 @@ func (wc *workflowEnvironmentInterceptor) GetSignalChannel(ctx Context, signalNa
 	return getWorkflowEnvOptions(ctx).getSignalChannel(ctx, signalName)
 }
 
+// GetSignalChannelWithOptions returns channel corresponding to the signal name, and applies the options to all the
+// signals received after the call. Use it to guard a workflow against signals with an oversize payload:
+//  ch := workflow.GetSignalChannelWithOptions(ctx, "my-signal", workflow.SignalChannelOptions{
+//    MaxPayloadSize: 1024,
+//    OversizeSignalHandler: func(signalName string, payload []byte) {
+//      droppedSignals++
+//    },
+//  })
+func GetSignalChannelWithOptions(ctx Context, signalName string, options SignalChannelOptions) Channel {
+	getWorkflowEnvOptions(ctx).signalChannelOptions[signalName] = options
+	return GetSignalChannel(ctx, signalName)
+}
+
+// GetDroppedSignalCount returns the number of signals with the given name that were not buffered because the signal
+// channel already held SignalChannelOptions.MaxBufferedSignals signals. Use it to stay aware of a signal flood without
+// keeping every signal in memory:
+//  ch := workflow.GetSignalChannelWithOptions(ctx, "my-signal", workflow.SignalChannelOptions{
+//    MaxBufferedSignals: 100,
+//  })
+//  ...
+//  if dropped := workflow.GetDroppedSignalCount(ctx, "my-signal"); dropped > 0 {
+//    logger.Warn("Signals dropped.", zap.Int("Count", dropped))
+//  }
+func GetDroppedSignalCount(ctx Context, signalName string) int {
+	return getWorkflowEnvOptions(ctx).droppedSignals[signalName]
+}
+
+// SortedSignalKeys returns the keys of a map of collected signal payloads in ascending order. Go randomizes map
+// iteration order, so ranging over such a map directly from workflow code is not deterministic and breaks replay.
+// Use it to process accumulated signals in a stable order:
+//  for _, key := range workflow.SortedSignalKeys(collected) {
+//    process(collected[key])
+//  }
+func SortedSignalKeys(collected map[string][]byte) []string {
+	keys := make([]string, 0, len(collected))
+	for key := range collected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SortedMapKeys returns the keys of m, a map with string keys, in ascending order. Go randomizes map iteration order,
+// so ranging over a map directly from workflow code is not deterministic and breaks replay. Use it to iterate over
+// any map in a stable order:
+//  keys, err := workflow.SortedMapKeys(pending)
+//  if err != nil {
+//    return err
+//  }
+//  for _, key := range keys {
+//    process(pending[key])
+//  }
+// It returns an error if m is not a map or if its key type is not a string kind.
+func SortedMapKeys(m interface{}) ([]string, error) {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("SortedMapKeys expects a map, got %T", m)
+	}
+	if v.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("SortedMapKeys expects a map with string keys, got %T", m)
+	}
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Partition splits items into the given number of contiguous groups, preserving the order of the items. The sizes of
+// the groups differ by at most one, the first groups being the larger ones, and some groups are empty when there are
+// fewer items than parts. The result only depends on the input, so it is deterministic, and can be used to fan out
+// a group of items per activity:
+//  for _, part := range workflow.Partition(items, 4) {
+//    futures = append(futures, workflow.ExecuteActivity(ctx, processItems, part))
+//  }
+// Partition panics if parts is not positive.
+func Partition(items []interface{}, parts int) [][]interface{} {
+	if parts <= 0 {
+		panic("Partition requires a positive number of parts")
+	}
+	result := make([][]interface{}, parts)
+	size, remainder := len(items)/parts, len(items)%parts
+	start := 0
+	for i := range result {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		result[i] = items[start:end:end]
+		start = end
+	}
+	return result
+}
+
+// SelectFutureOrSignal blocks until either the future becomes ready or a signal with the given name arrives, whichever
+// happens first. If the future is ready, futureReady is true and fv, ferr hold the value and error the future was set
+// with. Encoded results, like the ones of ExecuteActivity, are returned as []byte, call f.Get to decode them as it
+// no longer blocks. No signal is consumed in this case, so signals stay buffered on the channel returned by
+// GetSignalChannel for later processing. Otherwise futureReady is false and signalValue holds the received signal as
+// a Value to decode the payload from.
+//
+// Like Selector, it is deterministic: when both the future and a signal are already ready, the future wins.
+//  ready, _, _, sig := workflow.SelectFutureOrSignal(ctx, activityFuture, "cancel")
+//  if !ready {
+//    var reason string
+//    sig.(encoded.Value).Get(&reason)
+//  }
+func SelectFutureOrSignal(ctx Context, f Future, signalName string) (futureReady bool, fv interface{}, ferr error, signalValue interface{}) {
+	ch := GetSignalChannel(ctx, signalName)
+	NewSelector(ctx).
+		AddFuture(f, func(f Future) {
+			futureReady = true
+			fv, ferr = f.(asyncFuture).GetValueAndError()
+		}).
+		AddReceive(ch, func(c Channel, more bool) {
+			v, _, _ := c.(*channelImpl).receiveAsyncImpl(nil)
+			if payload, ok := v.([]byte); ok {
+				v = newEncodedValue(payload, getDataConverterFromWorkflowContext(ctx))
+			}
+			signalValue = v
+		}).
+		Select(ctx)
+	return futureReady, fv, ferr, signalValue
+}
+
 func newEncodedValue(value []byte, dc DataConverter) Value {
 	if dc == nil {
 		dc = getDefaultDataConverter()
@@ -1258,6 +2039,17 @@ func (wc *workflowEnvironmentInterceptor) GetVersion(ctx Context, changeID strin
 	return wc.env.GetVersion(changeID, minSupported, maxSupported)
 }
 
+// GetVersionHistory returns the versions of all the changeIDs recorded so far in this run, keyed by changeID. A version
+// is recorded by the first GetVersion call for its changeID, or found in the history on replay. Use it to audit which
+// code paths an execution took:
+//  for changeID, version := range workflow.GetVersionHistory(ctx) {
+//    logger.Info("Change version.", zap.String("ChangeID", changeID), zap.Int("Version", int(version)))
+//  }
+// The returned map is a copy, so changing it has no effect on the workflow.
+func GetVersionHistory(ctx Context) map[string]Version {
+	return getWorkflowEnvironment(ctx).GetVersions()
+}
+
 // SetQueryHandler sets the query handler to handle workflow query. The queryType specify which query type this handler
 // should handle. The handler must be a function that returns 2 values. The first return value must be a serializable
 // result. The second return value must be an error. The handler function could receive any number of input parameters.
@@ -1330,6 +2122,24 @@ func (wc *workflowEnvironmentInterceptor) IsReplaying(ctx Context) bool {
 	return wc.env.IsReplaying()
 }
 
+// IsFirstDecision returns true while the workflow code runs as part of the first decision task of the workflow run,
+// that is before any prior decision task of the run completed. Unlike IsReplaying, the value is derived from the
+// workflow history and is the same on replay, so it is safe to use it to make decisions, for example to run one-time
+// initialization.
+func IsFirstDecision(ctx Context) bool {
+	return getWorkflowEnvironment(ctx).IsFirstDecision()
+}
+
+// GetStackTrace returns the stack traces of all the coroutines of the workflow, the calling one included, in the
+// format of a goroutine dump. Each stack trace starts with the name of the coroutine, as given to GoNamed, and what it
+// is blocked on, for example "coroutine poller [blocked on updates.Receive]:". Use it to diagnose a workflow that does
+// not make progress:
+//  workflow.GetLogger(ctx).Debug("Waiting for updates", zap.String("StackTrace", workflow.GetStackTrace(ctx)))
+func GetStackTrace(ctx Context) string {
+	state := getState(ctx)
+	return state.dispatcher.stackTrace(state)
+}
+
 // HasLastCompletionResult checks if there is completion result from previous runs.
 // This is used in combination with cron schedule. A workflow can be started with an optional cron schedule.
 // If a cron workflow wants to pass some data to next schedule, it can return any data and that data will become
@@ -1378,8 +2188,10 @@ func WithActivityOptions(ctx Context, options ActivityOptions) Context {
 	eap.ScheduleToStartTimeoutSeconds = common.Int32Ceil(options.ScheduleToStartTimeout.Seconds())
 	eap.HeartbeatTimeoutSeconds = common.Int32Ceil(options.HeartbeatTimeout.Seconds())
 	eap.WaitForCancellation = options.WaitForCancellation
+	eap.CancellationGracePeriodSeconds = common.Int32Ceil(options.CancellationGracePeriod.Seconds())
 	eap.ActivityID = common.StringPtr(options.ActivityID)
 	eap.RetryPolicy = convertRetryPolicy(options.RetryPolicy)
+	eap.OnComplete = options.OnComplete
 	return ctx1
 }
 
@@ -1445,6 +2257,14 @@ func WithWaitForCancellation(ctx Context, wait bool) Context {
 	return ctx1
 }
 
+// withCancellationGracePeriod bounds the wait for the cancellation of the activity, see
+// ExecuteActivityParameters.CancellationGracePeriodSeconds.
+func withCancellationGracePeriod(ctx Context, seconds int32) Context {
+	ctx1 := setActivityParametersIfNotExist(ctx)
+	getActivityOptions(ctx1).CancellationGracePeriodSeconds = seconds
+	return ctx1
+}
+
 // WithRetryPolicy adds retry policy to the copy of the context
 func WithRetryPolicy(ctx Context, retryPolicy RetryPolicy) Context {
 	ctx1 := setActivityParametersIfNotExist(ctx)
@@ -43,11 +43,19 @@ import (
 // WorkflowReplayer is used to replay workflow code from an event history
 type WorkflowReplayer struct {
 	registry *registry
+
+	// encoded results replacing the recorded ones of completed activities, by activity type and by activity ID
+	activityResultsByType map[string][]byte
+	activityResultsByID   map[string][]byte
 }
 
 // NewWorkflowReplayer creates an instance of the WorkflowReplayer
 func NewWorkflowReplayer() *WorkflowReplayer {
-	return &WorkflowReplayer{registry: newRegistry()}
+	return &WorkflowReplayer{
+		registry:              newRegistry(),
+		activityResultsByType: make(map[string][]byte),
+		activityResultsByID:   make(map[string][]byte),
+	}
 }
 
 // RegisterWorkflow registers workflow function to replay
@@ -60,6 +68,31 @@ func (r *WorkflowReplayer) RegisterWorkflowWithOptions(w interface{}, options Re
 	r.registry.RegisterWorkflowWithOptions(w, options)
 }
 
+// OverrideActivityResult makes the following replays use result, instead of the recorded one, as the result of every
+// completed activity of the given type. The activity is a function or an activity type name, like for
+// workflow.ExecuteActivity. It allows to check how the workflow would have behaved had the activity returned something
+// else. A workflow that takes a different path usually makes different decisions, in which case the replay reports
+// the mismatch with the history as an error. The result is encoded with the default data converter.
+func (r *WorkflowReplayer) OverrideActivityResult(activity interface{}, result interface{}) error {
+	data, err := encodeArg(nil, result)
+	if err != nil {
+		return err
+	}
+	r.activityResultsByType[getActivityFunctionName(r.registry, activity)] = data
+	return nil
+}
+
+// OverrideActivityResultByID is OverrideActivityResult for the activity with the given activity ID. It takes
+// precedence over the overrides by activity type.
+func (r *WorkflowReplayer) OverrideActivityResultByID(activityID string, result interface{}) error {
+	data, err := encodeArg(nil, result)
+	if err != nil {
+		return err
+	}
+	r.activityResultsByID[activityID] = data
+	return nil
+}
+
 // ReplayWorkflowHistory executes a single decision task for the given history.
 // Use for testing backwards compatibility of code changes and troubleshooting workflows in a debugger.
 // The logger is an optional parameter. Defaults to the noop logger.
@@ -144,6 +177,9 @@ func (r *WorkflowReplayer) replayWorkflowHistory(
 	history *shared.History,
 ) error {
 	taskList := "ReplayTaskList"
+	if history != nil {
+		history = r.overrideActivityResults(history)
+	}
 	events := history.Events
 	if events == nil {
 		return errors.New("empty events")
@@ -234,6 +270,42 @@ func (r *WorkflowReplayer) replayWorkflowHistory(
 	return err
 }
 
+// overrideActivityResults returns a copy of history in which the results of the completed activities are replaced
+// according to the overrides. History is returned as is when there is nothing to override.
+func (r *WorkflowReplayer) overrideActivityResults(history *shared.History) *shared.History {
+	if len(r.activityResultsByType) == 0 && len(r.activityResultsByID) == 0 {
+		return history
+	}
+	scheduled := make(map[int64]*shared.ActivityTaskScheduledEventAttributes)
+	events := make([]*shared.HistoryEvent, len(history.Events))
+	for i, event := range history.Events {
+		events[i] = event
+		switch event.GetEventType() {
+		case shared.EventTypeActivityTaskScheduled:
+			scheduled[event.GetEventId()] = event.ActivityTaskScheduledEventAttributes
+		case shared.EventTypeActivityTaskCompleted:
+			attributes := event.ActivityTaskCompletedEventAttributes
+			s, ok := scheduled[attributes.GetScheduledEventId()]
+			if !ok {
+				continue
+			}
+			result, ok := r.activityResultsByID[s.GetActivityId()]
+			if !ok && s.ActivityType != nil {
+				result, ok = r.activityResultsByType[s.ActivityType.GetName()]
+			}
+			if !ok {
+				continue
+			}
+			overridden := *attributes
+			overridden.Result = result
+			e := *event
+			e.ActivityTaskCompletedEventAttributes = &overridden
+			events[i] = &e
+		}
+	}
+	return &shared.History{Events: events}
+}
+
 func extractHistoryFromFile(jsonfileName string, lastEventID int64) (*shared.History, error) {
 	raw, err := ioutil.ReadFile(jsonfileName)
 	if err != nil {
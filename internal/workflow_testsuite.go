@@ -67,6 +67,27 @@ type (
 		runFn        func(args mock.Arguments)
 		waitDuration func() time.Duration
 	}
+
+	// DispatcherSnapshot describes the coroutines of the tested workflow once a decision task ran all of them until
+	// they got blocked. See TestWorkflowEnvironment.SetLivenessDetector.
+	DispatcherSnapshot struct {
+		// Now is the workflow time (a.k.a workflow.Now() time) of the decision task.
+		Now time.Time
+		// Coroutines are the coroutines of the workflow that did not return yet, in the order they were started.
+		Coroutines []CoroutineSnapshot
+	}
+
+	// CoroutineSnapshot describes a blocked coroutine of the tested workflow.
+	CoroutineSnapshot struct {
+		// Name is the name of the coroutine, as given to GoNamed, or its sequence number. The root coroutine is "1".
+		Name string
+		// BlockedSince is the workflow time of the first decision task since which the coroutine has been blocked with
+		// the same StackTrace. A coroutine that blocks at the same place again, like a loop that receives from a
+		// channel, is therefore reported as blocked since the first time it blocked there.
+		BlockedSince time.Time
+		// StackTrace is the stack trace of the coroutine where it is blocked.
+		StackTrace string
+	}
 )
 
 func newEncodedValues(values []byte, dc DataConverter) Values {
@@ -442,6 +463,33 @@ func (t *TestWorkflowEnvironment) SetTestTimeout(idleTimeout time.Duration) *Tes
 	return t
 }
 
+// SetSideEffectFixture sets recorded SideEffect results for the tested workflow, keyed by the order of the SideEffect
+// calls, starting at 0. A SideEffect call that has a result in the fixture returns it without invoking the side effect
+// function, the other calls invoke it as usual. The results are decoded with the data converter of the workflow:
+//  result, _ := encoded.GetDefaultDataConverter().ToData("recorded value")
+//  env.SetSideEffectFixture(map[int][]byte{0: result})
+func (t *TestWorkflowEnvironment) SetSideEffectFixture(fixture map[int][]byte) *TestWorkflowEnvironment {
+	t.impl.sideEffectFixture = fixture
+	return t
+}
+
+// SetLivenessDetector sets a detector that decides whether the tested workflow is making progress. It is called at the
+// end of every decision task with a snapshot of the blocked coroutines of the workflow, and of its child workflows.
+// When it returns an error, the workflow is completed with that error, which is returned by GetWorkflowError. Use it to
+// encode progress expectations, for example to fail when a coroutine stays blocked at the same place for too long:
+//  env.SetLivenessDetector(func(snapshot DispatcherSnapshot) error {
+//    for _, c := range snapshot.Coroutines {
+//      if c.Name == "approval" && snapshot.Now.Sub(c.BlockedSince) > time.Hour {
+//        return fmt.Errorf("approval is stuck:\n%v", c.StackTrace)
+//      }
+//    }
+//    return nil
+//  })
+func (t *TestWorkflowEnvironment) SetLivenessDetector(detector func(snapshot DispatcherSnapshot) error) *TestWorkflowEnvironment {
+	t.impl.livenessDetector = detector
+	return t
+}
+
 // SetWorkflowTimeout sets the execution timeout for this tested workflow. This test framework uses mock clock internally
 // and when workflow is blocked on timer, it will auto forward the mock clock. Use SetWorkflowTimeout() to enforce a
 // workflow execution timeout to return timeout error when the workflow mock clock is moved head of the timeout.
@@ -37,6 +37,13 @@ type (
 
 	// MockCallWrapper is a wrapper to mock.Call. It offers the ability to wait on workflow's clock instead of wall clock.
 	MockCallWrapper = internal.MockCallWrapper
+
+	// DispatcherSnapshot describes the coroutines of the tested workflow once a decision task ran all of them until
+	// they got blocked. See TestWorkflowEnvironment.SetLivenessDetector.
+	DispatcherSnapshot = internal.DispatcherSnapshot
+
+	// CoroutineSnapshot describes a blocked coroutine of the tested workflow.
+	CoroutineSnapshot = internal.CoroutineSnapshot
 )
 
 // ErrMockStartChildWorkflowFailed is special error used to indicate the mocked child workflow should fail to start.
@@ -146,6 +146,18 @@ type (
 		// RegisterWorkflowWithOptions registers workflow that is going to be replayed with user provided name
 		RegisterWorkflowWithOptions(w interface{}, options workflow.RegisterOptions)
 
+		// OverrideActivityResult makes the following replays use result, instead of the recorded one, as the result of
+		// every completed activity of the given type. The activity is a function or an activity type name, like for
+		// workflow.ExecuteActivity. It allows to check how the workflow would have behaved had the activity returned
+		// something else. A workflow that takes a different path usually makes different decisions, in which case the
+		// replay reports the mismatch with the history as an error. The result is encoded with the default data
+		// converter.
+		OverrideActivityResult(activity interface{}, result interface{}) error
+
+		// OverrideActivityResultByID is OverrideActivityResult for the activity with the given activity ID. It takes
+		// precedence over the overrides by activity type.
+		OverrideActivityResultByID(activityID string, result interface{}) error
+
 		// ReplayWorkflowHistory executes a single decision task for the given json history file.
 		// Use for testing the backwards compatibility of code changes and troubleshooting workflows in a debugger.
 		// The logger is an optional parameter. Defaults to the noop logger.
@@ -35,6 +35,13 @@ type LocalActivityOptions = internal.LocalActivityOptions
 // RetryPolicy specify how to retry activity if error happens.
 type RetryPolicy = internal.RetryPolicy
 
+// ErrActivityLimitExceeded is returned by the future of ExecuteActivity when the workflow run has already scheduled
+// the number of activities allowed by WithActivityLimit.
+var ErrActivityLimitExceeded = internal.ErrActivityLimitExceeded
+
+// ErrInvalidActivityParameters is wrapped by the error ExecuteActivityParameters.Validate returns.
+var ErrInvalidActivityParameters = internal.ErrInvalidActivityParameters
+
 // WithActivityOptions makes a copy of the context and adds the
 // passed in options to the context. If an activity options exists,
 // it will be overwritten by the passed in value as a whole.
@@ -51,6 +58,13 @@ func WithLocalActivityOptions(ctx Context, options LocalActivityOptions) Context
 	return internal.WithLocalActivityOptions(ctx, options)
 }
 
+// WithActivityLimit makes a copy of the current context and limits the total number of activities the workflow run
+// may schedule. Once the limit is reached, ExecuteActivity returns a future that fails with ErrActivityLimitExceeded.
+// Use it to catch runaway fan-out bugs before they bloat the workflow history. A limit of 0 means no limit.
+func WithActivityLimit(ctx Context, limit int) Context {
+	return internal.WithActivityLimit(ctx, limit)
+}
+
 // WithTaskList makes a copy of the current context and update the taskList
 // field in its activity options. An empty activity options will be created
 // if it does not exist in the original context.
@@ -111,3 +125,22 @@ func WithWaitForCancellation(ctx Context, wait bool) Context {
 func WithRetryPolicy(ctx Context, retryPolicy RetryPolicy) Context {
 	return internal.WithRetryPolicy(ctx, retryPolicy)
 }
+
+// ActivityOptionsBuilder builds ActivityOptions and validates the relationship between its timeouts.
+type ActivityOptionsBuilder = internal.ActivityOptionsBuilder
+
+// NewActivityOptions creates a builder that can be used to construct validated ActivityOptions, for example:
+//  ao, err := workflow.NewActivityOptions().
+//  	WithScheduleToStartTimeout(time.Minute).
+//  	WithStartToCloseTimeout(5 * time.Minute).
+//  	Build()
+//  if err != nil {
+//  	return err
+//  }
+//  ctx = workflow.WithActivityOptions(ctx, ao)
+//
+// Build returns an error if a mandatory timeout is missing or if StartToCloseTimeout is larger than
+// ScheduleToCloseTimeout.
+func NewActivityOptions() ActivityOptionsBuilder {
+	return internal.NewActivityOptions()
+}
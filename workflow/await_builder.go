@@ -0,0 +1,53 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+type (
+	// AwaitOutcome tells which case completed a wait built with NewAwaitBuilder.
+	AwaitOutcome = internal.AwaitOutcome
+
+	// AwaitBuilder builds a wait for a future that can be bounded by a timeout and interrupted by cancellation.
+	AwaitBuilder = internal.AwaitBuilder
+)
+
+const (
+	// AwaitOutcomeFuture indicates that the future became ready.
+	AwaitOutcomeFuture = internal.AwaitOutcomeFuture
+	// AwaitOutcomeTimeout indicates that the timeout elapsed before the future became ready.
+	AwaitOutcomeTimeout = internal.AwaitOutcomeTimeout
+	// AwaitOutcomeCanceled indicates that the context was canceled before the future became ready.
+	AwaitOutcomeCanceled = internal.AwaitOutcomeCanceled
+)
+
+// NewAwaitBuilder creates a builder for the common wait for a future, a timeout or a cancellation, which otherwise
+// requires building a Selector by hand:
+//  outcome, _, err := workflow.NewAwaitBuilder(ctx).
+//    Future(activityFuture).
+//    OrTimeout(time.Hour).
+//    OrCancel().
+//    Do()
+// When several cases are ready at the same time, the future wins over the timeout, and the timeout over cancellation.
+// Canceling the context cancels a pending timeout timer as well, which is reported as AwaitOutcomeCanceled.
+func NewAwaitBuilder(ctx Context) AwaitBuilder {
+	return internal.NewAwaitBuilder(ctx)
+}
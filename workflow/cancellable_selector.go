@@ -0,0 +1,41 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+// CancellableSelector is a Selector that also returns from Select when its context is canceled.
+// See NewCancellableSelector.
+type CancellableSelector = internal.CancellableSelector
+
+// NewCancellableSelector creates a Selector whose Select returns as soon as ctx is canceled, even if none of the added
+// cases can ever become ready. It prevents a coroutine from being stuck forever in Select on workflow cancellation:
+//  selector := workflow.NewCancellableSelector(ctx)
+//  selector.AddReceive(requests, handleRequest)
+//  selector.Select(ctx)
+//  if selector.WasCanceled() {
+//    return ctx.Err()
+//  }
+// The cancellation is checked after all the added cases, so a case that is ready at the same time wins. The default
+// case, if any, is only used when ctx is not canceled.
+func NewCancellableSelector(ctx Context) CancellableSelector {
+	return internal.NewCancellableSelector(ctx)
+}
@@ -21,6 +21,8 @@
 package workflow
 
 import (
+	"time"
+
 	"go.uber.org/cadence/internal"
 )
 
@@ -54,6 +56,46 @@ func WithCancel(parent Context) (ctx Context, cancel CancelFunc) {
 	return internal.WithCancel(parent)
 }
 
+// WithDeadline returns a copy of the parent context with the deadline adjusted
+// to be no later than d.  If the parent's deadline is already earlier than d,
+// WithDeadline(parent, d) is semantically equivalent to parent.  The returned
+// context's Done channel is closed when the deadline expires, when the returned
+// cancel function is called, or when the parent context's Done channel is
+// closed, whichever happens first.
+//
+// The deadline is measured on the workflow clock, see Now, and expires with a
+// workflow timer, so it is deterministic.
+//
+// Canceling this context releases resources associated with it, so code should
+// call cancel as soon as the operations running in this Context complete.
+func WithDeadline(parent Context, deadline time.Time) (Context, CancelFunc) {
+	return internal.WithDeadline(parent, deadline)
+}
+
+// WithTimeout returns WithDeadline(parent, Now(parent).Add(timeout)).
+//
+// Canceling this context releases resources associated with it, so code should
+// call cancel as soon as the operations running in this Context complete:
+//
+// 	func slowOperationWithTimeout(ctx workflow.Context) (Result, error) {
+// 		ctx, cancel := workflow.WithTimeout(ctx, time.Hour)
+// 		defer cancel()  // releases resources if slowOperation completes before timeout elapses
+// 		return slowOperation(ctx)
+// 	}
+func WithTimeout(parent Context, timeout time.Duration) (Context, CancelFunc) {
+	return internal.WithTimeout(parent, timeout)
+}
+
+// RemainingDeadline returns how long until the deadline of ctx, measured on the workflow clock, or false if ctx has
+// no deadline. The duration is negative once the deadline passed. Use it to derive the timeouts of the activities
+// and child workflows from the budget left to the caller:
+//  if remaining, ok := workflow.RemainingDeadline(ctx); ok {
+//    ctx = workflow.WithStartToCloseTimeout(ctx, remaining)
+//  }
+func RemainingDeadline(ctx Context) (time.Duration, bool) {
+	return internal.RemainingDeadline(ctx)
+}
+
 // WithValue returns a copy of parent in which the value associated with key is
 // val.
 //
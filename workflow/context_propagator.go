@@ -32,4 +32,25 @@ type (
 	// ContextPropagator is an interface that determines what information from
 	// context to pass along
 	ContextPropagator = internal.ContextPropagator
+
+	// BinaryMapKey is the key under which the propagator returned by NewBinaryMapPropagator stores the []byte value of
+	// a propagated key, in both Go contexts and workflow contexts.
+	BinaryMapKey = internal.BinaryMapKey
 )
+
+// NewBinaryMapPropagator returns a ContextPropagator that propagates the []byte values stored under BinaryMapKey(key)
+// for each of keys. Values are copied into the headers as is, so they can hold any binary payload, like a serialized
+// span context. Keys without a value in the context are not propagated.
+//  ctx = context.WithValue(ctx, workflow.BinaryMapKey("span"), spanBytes)
+//  ...
+//  spanBytes, _ := ctx.Value(workflow.BinaryMapKey("span")).([]byte)
+func NewBinaryMapPropagator(keys []string) ContextPropagator {
+	return internal.NewBinaryMapPropagator(keys)
+}
+
+// NewChainedPropagator returns a ContextPropagator that calls each of propagators in order, so that several of them
+// can be registered as one. Inject and InjectFromWorkflow stop at the first error. Extract and ExtractToWorkflow pass
+// the context returned by a propagator to the next one, and stop at the first error as well.
+func NewChainedPropagator(propagators ...ContextPropagator) ContextPropagator {
+	return internal.NewChainedPropagator(propagators...)
+}
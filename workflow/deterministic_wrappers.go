@@ -47,6 +47,13 @@ type (
 	// WaitGroup is used to wait for a collection of
 	// coroutines to finish
 	WaitGroup = internal.WaitGroup
+
+	// Mutex is used to protect state that is shared by several coroutines.
+	// See more: workflow.NewMutex(ctx).
+	Mutex = internal.Mutex
+
+	// TimerOptions stores callbacks for a timer. See workflow.NewTimerWithOptions(ctx).
+	TimerOptions = internal.TimerOptions
 )
 
 // Await blocks the calling thread until condition() returns true.
@@ -84,6 +91,22 @@ func NewNamedBufferedChannel(ctx Context, name string, size int) Channel {
 	return internal.NewNamedBufferedChannel(ctx, name, size)
 }
 
+// Drain receives all the values that can be received from the channel without blocking and returns them in the
+// order they were sent. It does not wait for the channel to be closed, so for an open channel it returns whatever is
+// buffered at the moment of the call. Values of signal channels are the encoded signal payloads ([]byte). It is
+// Channel.ReceiveAll, except that it returns nil instead of an empty slice when there is nothing to receive.
+func Drain(ch Channel) []interface{} {
+	return internal.Drain(ch)
+}
+
+// CollectUniqueN receives values from c until it collected n values with distinct keys, and returns them in the order
+// they were received. keyOf returns the key of a value, values whose key was already seen are dropped. Like Drain, it
+// passes the values of signal channels to keyOf and returns them as the encoded signal payloads ([]byte).
+// It returns fewer than n values if c is closed or ctx is canceled before enough unique values arrived.
+func CollectUniqueN(ctx Context, c Channel, n int, keyOf func(interface{}) string) []interface{} {
+	return internal.CollectUniqueN(ctx, c, n, keyOf)
+}
+
 // NewSelector creates a new Selector instance.
 func NewSelector(ctx Context) Selector {
 	return internal.NewSelector(ctx)
@@ -100,6 +123,11 @@ func NewWaitGroup(ctx Context) WaitGroup {
 	return internal.NewWaitGroup(ctx)
 }
 
+// NewMutex creates a new Mutex instance.
+func NewMutex(ctx Context) Mutex {
+	return internal.NewMutex(ctx)
+}
+
 // Go creates a new coroutine. It has similar semantic to goroutine in a context of the workflow.
 func Go(ctx Context, f func(ctx Context)) {
 	internal.Go(ctx, f)
@@ -117,6 +145,12 @@ func NewFuture(ctx Context) (Future, Settable) {
 	return internal.NewFuture(ctx)
 }
 
+// NewNamedFuture creates a new future with a given human readable name as well as associated Settable.
+// Name appears in stack traces of coroutines that are blocked on this Future.
+func NewNamedFuture(ctx Context, name string) (Future, Settable) {
+	return internal.NewNamedFuture(ctx, name)
+}
+
 // Now returns the current time when the decision is started or replayed.
 // The workflow needs to use this Now() to get the wall clock time instead of the Go lang library one.
 func Now(ctx Context) time.Time {
@@ -133,6 +167,21 @@ func NewTimer(ctx Context, d time.Duration) Future {
 	return internal.NewTimer(ctx, d)
 }
 
+// NewTimerWithOptions returns a timer future like NewTimer, and invokes the callbacks of the options when the timer
+// fires or gets canceled. The callbacks run as part of the workflow execution before the returned Future becomes
+// ready, and they are called on replay as well, so they have to be deterministic.
+func NewTimerWithOptions(ctx Context, d time.Duration, options TimerOptions) Future {
+	return internal.NewTimerWithOptions(ctx, d, options)
+}
+
+// NewTimerWithDeadline returns a timer future like NewTimer, along with the time at which the timer is scheduled to
+// fire according to the workflow clock, that is workflow.Now(ctx).Add(d). Use the deadline for logging or to compute
+// the time remaining before the timer fires instead of relying on time.Now(), which is not replay safe.
+// A non positive duration returns the current workflow time as deadline.
+func NewTimerWithDeadline(ctx Context, d time.Duration) (Future, time.Time) {
+	return internal.NewTimerWithDeadline(ctx, d)
+}
+
 // Sleep pauses the current workflow for at least the duration d. A negative or zero duration causes Sleep to return
 // immediately. Workflow code needs to use this Sleep() to sleep instead of the Go lang library one(timer.Sleep()).
 // You can cancel the pending sleep by cancel the Context (using context from workflow.WithCancel(ctx)).
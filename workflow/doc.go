@@ -213,6 +213,14 @@ Get() methods of the future at a later time.
 To implement more complex wait conditions on the returned future objects, use the workflow.Selector class. Take a look
 at our Pickfirst sample for an example of how to use of workflow.Selector.
 
+With Go 1.21 or later, a future can be wrapped into a workflow.TypedFuture, whose Get() returns the result as a value
+of the type parameter instead of decoding it into an output parameter:
+
+	result, err := workflow.NewTypedFutureFrom[string](workflow.ExecuteActivity(ctx, SimpleActivity, value)).Get(ctx)
+
+workflow.TypedFuture, workflow.NewTypedFuture and workflow.NewTypedFutureFrom require Go 1.21 or later to build the
+workflow code, they are not defined when building with older Go versions.
+
 Child Workflow
 
 workflow.ExecuteChildWorkflow enables the scheduling of other workflows from within a workflow's implementation. The
@@ -0,0 +1,64 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+type (
+	// EntityWorkflowOptions configure an entity workflow created by NewEntityWorkflow.
+	EntityWorkflowOptions = internal.EntityWorkflowOptions
+
+	// EntityCommandHandler applies a single command to the state of an entity.
+	EntityCommandHandler = internal.EntityCommandHandler
+
+	// EntityWorkflow runs the loop of a long-lived workflow that owns the state of a single entity and processes
+	// commands sent to it as signals.
+	EntityWorkflow = internal.EntityWorkflow
+)
+
+// NewEntityWorkflow creates an EntityWorkflow for the state pointed to by statePtr. It implements the pattern of one
+// long-lived workflow per entity:
+//
+// • Every command is a signal, the command name is the signal name. Handlers are invoked one at a time in the order the
+// commands were received.
+//
+// • The current state is returned by a query, "state" by default.
+//
+// • After EntityWorkflowOptions.MaxCommandsPerRun commands, or once the history reached
+// EntityWorkflowOptions.MaxHistoryLength events, the workflow continues as new with the state as its only argument,
+// which keeps the history of a single run bounded.
+//
+// Handlers are workflow code, so they must be deterministic. For example:
+//  func AccountWorkflow(ctx workflow.Context, state Account) error {
+//    return workflow.NewEntityWorkflow(&state, workflow.EntityWorkflowOptions{}).
+//      OnCommand("deposit", func(ctx workflow.Context, command encoded.Value) error {
+//        var amount int
+//        if err := command.Get(&amount); err != nil {
+//          return err
+//        }
+//        state.Balance += amount
+//        return nil
+//      }).
+//      Run(ctx, AccountWorkflow)
+//  }
+func NewEntityWorkflow(statePtr interface{}, options EntityWorkflowOptions) EntityWorkflow {
+	return internal.NewEntityWorkflow(statePtr, options)
+}
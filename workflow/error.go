@@ -135,3 +135,18 @@ func NewTimeoutError(timeoutType shared.TimeoutType, details ...interface{}) *Ti
 func NewHeartbeatTimeoutError(details ...interface{}) *TimeoutError {
 	return internal.NewHeartbeatTimeoutError(details...)
 }
+
+// GetLastHeartbeatDetails returns the encoded details of the last heartbeat that an activity recorded before it timed
+// out, if err is the *TimeoutError of such an activity. The details are recorded by the server along with the timeout
+// and are part of the workflow history, so they are the same on replay. When the activity is retried by the server
+// because of a RetryPolicy, each new attempt reads the details of the previous one with activity.GetHeartbeatDetails,
+// and the workflow only receives the error, with the details of the last attempt, once the retries are exhausted.
+// The workflow can then pass the details to a new invocation of the activity to resume from the last checkpoint:
+//  err := workflow.ExecuteActivity(ctx, processFile, path, nil).Get(ctx, nil)
+//  if details, ok := workflow.GetLastHeartbeatDetails(err); ok {
+//    err = workflow.ExecuteActivity(ctx, processFile, path, details).Get(ctx, nil)
+//  }
+// The details are encoded with the data converter used by the activity worker.
+func GetLastHeartbeatDetails(err error) ([]byte, bool) {
+	return internal.GetLastHeartbeatDetails(err)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+// FairAllocator shares a fixed number of tokens among the coroutines of a workflow, granting them in the order they
+// were requested. See NewFairAllocator.
+type FairAllocator = internal.FairAllocator
+
+// NewFairAllocator creates a FairAllocator with capacity tokens. Coroutines request a weighted number of tokens with
+// Acquire and give them back with Release. Requests are granted strictly in the order they were made, so no waiter
+// starves, not even one asking for many tokens while others keep asking for few:
+//  allocator := workflow.NewFairAllocator(ctx, 10)
+//  if err := allocator.Acquire(ctx, 3); err != nil {
+//    return err
+//  }
+//  defer allocator.Release(3)
+// NewFairAllocator panics if capacity is not positive.
+func NewFairAllocator(ctx Context, capacity int) FairAllocator {
+	return internal.NewFairAllocator(ctx, capacity)
+}
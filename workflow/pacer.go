@@ -0,0 +1,44 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence/internal"
+)
+
+// Pacer spaces out the work of a workflow evenly over time, like a leaky bucket. See NewPacer.
+type Pacer = internal.Pacer
+
+// NewPacer creates a Pacer that lets the workflow proceed at most once per interval. Unlike a rate limiter it never
+// allows bursts, which is useful to schedule activities evenly against a downstream service:
+//  pacer := workflow.NewPacer(ctx, time.Minute)
+//  for _, item := range items {
+//    if err := pacer.Next(ctx); err != nil {
+//      return err
+//    }
+//    workflow.ExecuteActivity(ctx, processItem, item)
+//  }
+// The waiting is implemented with workflow timers, so it is deterministic.
+func NewPacer(ctx Context, interval time.Duration) Pacer {
+	return internal.NewPacer(ctx, interval)
+}
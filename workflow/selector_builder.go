@@ -0,0 +1,40 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+// SelectorBuilder accumulates Selector cases so that they can be inspected before the Selector is built.
+type SelectorBuilder = internal.SelectorBuilder
+
+// NewSelectorBuilder creates a builder for a Selector. Unlike a Selector, the builder can report how many cases were
+// added, which lets code that generates cases programmatically detect an empty set that would block forever:
+//  builder := workflow.NewSelectorBuilder(ctx)
+//  for _, f := range futures {
+//    builder.AddFuture(f, handle)
+//  }
+//  if builder.CaseCount() == 0 && !builder.HasDefault() {
+//    return errors.New("nothing to wait for")
+//  }
+//  builder.Build().Select(ctx)
+func NewSelectorBuilder(ctx Context) SelectorBuilder {
+	return internal.NewSelectorBuilder(ctx)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+// ShutdownGroup runs background coroutines of a workflow and lets the workflow stop them and wait for them to exit
+// before it completes. See NewShutdownGroup.
+type ShutdownGroup = internal.ShutdownGroup
+
+// NewShutdownGroup creates a ShutdownGroup. It makes sure that background coroutines are done with their work, for
+// example with the activity they were waiting for, before the workflow function returns:
+//  group := workflow.NewShutdownGroup(ctx)
+//  group.Go(ctx, func(ctx workflow.Context) {
+//    for done := false; !done; {
+//      selector := workflow.NewSelector(ctx)
+//      selector.AddReceive(group.Done(), func(c workflow.Channel, more bool) { done = true })
+//      selector.AddReceive(requests, handleRequest)
+//      selector.Select(ctx)
+//    }
+//  })
+//  ...
+//  group.Wait(ctx)
+//  return nil
+func NewShutdownGroup(ctx Context) ShutdownGroup {
+	return internal.NewShutdownGroup(ctx)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"go.uber.org/cadence/encoded"
+	"go.uber.org/cadence/internal"
+)
+
+// SignalHandlerOptions configures SetSignalHandler.
+type SignalHandlerOptions = internal.SignalHandlerOptions
+
+// SetSignalHandler calls handler in a new coroutine for every signal named signalName, with the signal as a Value to
+// decode the payload from. Signals are consumed from the channel returned by GetSignalChannel, so do not receive from
+// that channel elsewhere. No new handler is started once ctx is canceled, running handlers see the cancellation
+// through their ctx.
+//  workflow.SetSignalHandler(ctx, "order", func(ctx workflow.Context, signal encoded.Value) {
+//    var order Order
+//    signal.Get(&order)
+//    ...
+//  }, workflow.SignalHandlerOptions{MaxConcurrentHandlers: 2})
+// SetSignalHandler panics if MaxConcurrentHandlers is negative.
+func SetSignalHandler(ctx Context, signalName string, handler func(ctx Context, signal encoded.Value), options SignalHandlerOptions) {
+	internal.SetSignalHandler(ctx, signalName, handler, options)
+}
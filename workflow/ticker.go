@@ -0,0 +1,44 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"time"
+
+	"go.uber.org/cadence/internal"
+)
+
+// Ticker delivers ticks at a fixed interval of the workflow clock. See NewTicker.
+type Ticker = internal.Ticker
+
+// NewTicker creates a Ticker that ticks every interval, starting one interval after its creation. It replaces the
+// hand written loop around NewTimer of polling workflows:
+//  ticker := workflow.NewTicker(ctx, time.Minute)
+//  for ticker.Next(ctx) {
+//    ...
+//  }
+// Ticks are scheduled relative to the creation time rather than to the previous call to Next, so the time spent
+// between two calls does not make the ticks drift. Ticks that were missed because the workflow was busy for longer
+// than an interval are dropped. Next returns false when the context is canceled.
+// NewTicker panics if interval is not positive.
+func NewTicker(ctx Context, interval time.Duration) Ticker {
+	return internal.NewTicker(ctx, interval)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2017-2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build go1.21
+// +build go1.21
+
+// The module declares go 1.13, which does not allow type parameters. Go 1.21 and later build a file that has a
+// go1.21 build constraint at the language version of the constraint, so this file is left out by older toolchains
+// and built as Go 1.21 code by newer ones, without raising the go version of the module.
+
+package workflow
+
+import "go.uber.org/cadence/internal"
+
+type (
+	// TypedFuture is a Future with a result of type T.
+	// Requires Go 1.21 or later, the type is not defined when building with older Go versions.
+	TypedFuture[T any] interface {
+		internal.TypedFuture[T]
+	}
+
+	// TypedSettable is used to set value or error on a TypedFuture.
+	// Requires Go 1.21 or later, the type is not defined when building with older Go versions.
+	TypedSettable[T any] interface {
+		internal.TypedSettable[T]
+	}
+)
+
+// NewTypedFuture creates a new TypedFuture as well as associated TypedSettable that is used to set its value.
+// Unlike Future.Get, TypedFuture.Get returns an error instead of panicking when the value the future was set with is
+// not a T.
+// Requires Go 1.21 or later, the function is not defined when building with older Go versions.
+func NewTypedFuture[T any](ctx Context) (TypedFuture[T], TypedSettable[T]) {
+	return internal.NewTypedFuture[T](ctx)
+}
+
+// NewTypedFutureFrom wraps a Future, for example the one returned by ExecuteActivity, into a TypedFuture:
+//
+//	f := workflow.NewTypedFutureFrom[string](workflow.ExecuteActivity(ctx, MyActivity))
+//	result, err := f.Get(ctx)
+//
+// Requires Go 1.21 or later, the function is not defined when building with older Go versions.
+func NewTypedFutureFrom[T any](future Future) TypedFuture[T] {
+	return internal.NewTypedFutureFrom[T](future)
+}
@@ -21,6 +21,8 @@
 package workflow
 
 import (
+	"time"
+
 	"github.com/uber-go/tally"
 	"go.uber.org/cadence/encoded"
 	"go.uber.org/cadence/internal"
@@ -49,6 +51,22 @@ type (
 
 	// Info information about currently executing workflow
 	Info = internal.WorkflowInfo
+
+	// SignalChannelOptions stores options for a signal channel. See GetSignalChannelWithOptions call.
+	SignalChannelOptions = internal.SignalChannelOptions
+
+	// ExecuteActivityParameters describes a single activity invocation of ExecuteActivitiesAsync.
+	ExecuteActivityParameters = internal.ExecuteActivityParameters
+
+	// TaskListKind is the kind of the task list a decision task was polled from. See GetCurrentTaskListKind call.
+	TaskListKind = internal.TaskListKind
+)
+
+const (
+	// TaskListKindNormal is the task list the workflow was started on.
+	TaskListKindNormal = internal.TaskListKindNormal
+	// TaskListKindSticky is the task list of the worker that has the workflow execution cached.
+	TaskListKindSticky = internal.TaskListKindSticky
 )
 
 // Register - registers a workflow function with the framework.
@@ -112,6 +130,113 @@ func ExecuteActivity(ctx Context, activity interface{}, args ...interface{}) Fut
 	return internal.ExecuteActivity(ctx, activity, args...)
 }
 
+// ExecuteActivityWithRetry requests activity execution like ExecuteActivity, retrying failed attempts from within the
+// workflow according to the given retry policy. Attempts that fail with CustomError, GenericError, PanicError or
+// TimeoutError are retried after a backoff computed from the policy, using a deterministic workflow timer between
+// attempts. Retry stops when the error reason is listed in NonRetriableErrorReasons, when MaximumAttempts is reached or
+// when ExpirationInterval has elapsed, in which case the future is resolved with the last attempt's error.
+//
+// Unlike RetryPolicy on ActivityOptions, which is handled by the Cadence server, each attempt is recorded as a separate
+// activity in the workflow history.
+// Canceling the context (workflow.WithCancel(ctx)) aborts the retry loop and resolves the future with CanceledError.
+// Retries also stop once the RetryBudget of the context, if any, is exhausted, see WithRetryBudget.
+//
+// RetryActivity is the blocking form that takes the activity as ExecuteActivityParameters and returns the encoded
+// result:
+//  result, err := workflow.RetryActivity(ctx, workflow.ExecuteActivityParameters{Activity: MyActivity}, retryPolicy)
+//
+// ExecuteActivityWithRetry returns Future with activity result or failure.
+func ExecuteActivityWithRetry(ctx Context, retryPolicy RetryPolicy, activity interface{}, args ...interface{}) Future {
+	return internal.ExecuteActivityWithRetry(ctx, retryPolicy, activity, args...)
+}
+
+// BackoffUntilDeadline calls attempt until it reports done, backing off between the calls according to policy, but
+// gives up once the workflow clock would pass deadline. The backoffs are deterministic workflow timers:
+//  err := workflow.BackoffUntilDeadline(ctx, policy, workflow.Now(ctx).Add(time.Hour), func() (bool, error) {
+//    var status string
+//    err := workflow.ExecuteActivity(ctx, checkStatus).Get(ctx, &status)
+//    return status == "ready", err
+//  })
+// It returns nil as soon as attempt returns true, and the error of attempt as soon as it returns one. It returns a
+// TimeoutError of type ScheduleToClose when the next attempt would start after deadline, after ExpirationInterval of
+// policy or when MaximumAttempts attempts were made. The other fields of policy are used as with
+// ExecuteActivityWithRetry, MaximumAttempts and ExpirationInterval are optional as deadline already bounds the attempts.
+func BackoffUntilDeadline(ctx Context, policy RetryPolicy, deadline time.Time, attempt func() (bool, error)) error {
+	return internal.BackoffUntilDeadline(ctx, policy, deadline, attempt)
+}
+
+// ExecuteActivitiesAsync requests execution of a batch of activities, in the order of params, and returns their
+// futures positionally. Use AwaitAll to wait for all of them:
+//  futures := workflow.ExecuteActivitiesAsync(ctx, []workflow.ExecuteActivityParameters{
+//    {Activity: activityA, Args: []interface{}{"a"}},
+//    {Activity: activityB, Args: []interface{}{"b"}},
+//  })
+//  _, errs := workflow.AwaitAll(ctx, futures)
+// Activities whose parameters fail ExecuteActivityParameters.Validate are not scheduled, their futures fail with the
+// error of Validate.
+func ExecuteActivitiesAsync(ctx Context, params []ExecuteActivityParameters) []Future {
+	return internal.ExecuteActivitiesAsync(ctx, params)
+}
+
+// AwaitAll blocks until all the futures are ready, and returns their values and errors positionally, regardless of
+// the order in which the futures became ready. Encoded results, like the ones of ExecuteActivity, are returned as
+// []byte, call Get on the corresponding future to decode them as it no longer blocks.
+func AwaitAll(ctx Context, futures []Future) ([]interface{}, []error) {
+	return internal.AwaitAll(ctx, futures)
+}
+
+// ExecuteActivities executes a batch of activities, in the order of params, and blocks until all of them completed.
+// The encoded results and errors are returned positionally, results[i] and errs[i] belong to params[i]. Decode a
+// result with the data converter of the context, or use ExecuteActivitiesAsync to decode the results with Get.
+// Canceling ctx cancels all the activities that are still outstanding, their errors are then CanceledError.
+func ExecuteActivities(ctx Context, params []ExecuteActivityParameters) (results [][]byte, errs []error) {
+	return internal.ExecuteActivities(ctx, params)
+}
+
+// RetryActivity executes the activity described by params and blocks until it completed, retrying failed attempts from
+// within the workflow according to policy, like ExecuteActivityWithRetry. It returns the encoded result of the first
+// successful attempt. Retry stops on a non retryable error, when MaximumAttempts is reached, when ExpirationInterval has
+// elapsed or when ctx is canceled; the error of the last attempt, or CanceledError, is then returned.
+// Backoff between attempts uses workflow timers, so the retries are replayed deterministically.
+func RetryActivity(ctx Context, params ExecuteActivityParameters, policy RetryPolicy) ([]byte, error) {
+	return internal.RetryActivity(ctx, params, policy)
+}
+
+// CallActivity executes the activity named activityType with input, which is used as is, and blocks until it
+// completed. It returns the encoded result of the activity. input has to be encoded with the data converter the
+// activity worker uses, like an ExecuteActivity argument would be.
+// The activity is scheduled with the activity options of ctx, so set the defaults once with WithActivityOptions and
+// override them for a single call with the other modifiers, which win over the defaults:
+//  ctx = workflow.WithActivityOptions(ctx, defaultOptions)
+//  result, err := workflow.CallActivity(workflow.WithStartToCloseTimeout(ctx, time.Hour), "slowActivity", input)
+func CallActivity(ctx Context, activityType string, input []byte) ([]byte, error) {
+	return internal.CallActivity(ctx, activityType, input)
+}
+
+// AwaitAny blocks until the first of the futures becomes ready, and returns its position in futures along with its
+// value and error. When several futures are already ready, the one with the lowest index is returned. Encoded results,
+// like the ones of ExecuteActivity, are returned as []byte, call Get on futures[index] to decode them.
+// If ctx is canceled before any future is ready, AwaitAny returns -1 and ErrCanceled.
+func AwaitAny(ctx Context, futures []Future) (index int, value interface{}, err error) {
+	return internal.AwaitAny(ctx, futures)
+}
+
+// MergeSortedResults waits for all the futures, each resolving to a chunk of values sorted according to less, and
+// merges the chunks into a single sorted slice. Each chunk is retrieved with Get into a []interface{}, so encoded
+// results, like the ones of ExecuteActivity, are decoded with the data converter of the future. Values that are equal
+// according to less keep the order of their futures, so the merge is deterministic. It returns the error of the first
+// future, in the order of futures, that failed:
+//  var futures []workflow.Future
+//  for _, shard := range shards {
+//    futures = append(futures, workflow.ExecuteActivity(ctx, sortShard, shard))
+//  }
+//  sorted, err := workflow.MergeSortedResults(ctx, futures, func(a, b interface{}) bool {
+//    return a.(string) < b.(string)
+//  })
+func MergeSortedResults(ctx Context, futures []Future, less func(a, b interface{}) bool) ([]interface{}, error) {
+	return internal.MergeSortedResults(ctx, futures, less)
+}
+
 // ExecuteLocalActivity requests to run a local activity. A local activity is like a regular activity with some key
 // differences:
 //
@@ -178,6 +303,13 @@ func GetInfo(ctx Context) *Info {
 	return internal.GetWorkflowInfo(ctx)
 }
 
+// GetCurrentTaskListKind returns whether the current decision task was polled from the sticky task list of the worker
+// or from the normal task list of the workflow. It is meant for diagnosing sticky execution and must not be used to
+// make decisions in the workflow code, as the value is not part of the workflow history.
+func GetCurrentTaskListKind(ctx Context) TaskListKind {
+	return internal.GetCurrentTaskListKind(ctx)
+}
+
 // GetLogger returns a logger to be used in workflow's context
 func GetLogger(ctx Context) *zap.Logger {
 	return internal.GetLogger(ctx)
@@ -217,6 +349,69 @@ func GetSignalChannel(ctx Context, signalName string) Channel {
 	return internal.GetSignalChannel(ctx, signalName)
 }
 
+// GetSignalChannelWithOptions returns channel corresponding to the signal name, and applies the options to all the
+// signals received after the call. Signals with a payload larger than SignalChannelOptions.MaxPayloadSize are not
+// delivered to the channel and are passed to SignalChannelOptions.OversizeSignalHandler instead.
+func GetSignalChannelWithOptions(ctx Context, signalName string, options SignalChannelOptions) Channel {
+	return internal.GetSignalChannelWithOptions(ctx, signalName, options)
+}
+
+// GetDroppedSignalCount returns the number of signals with the given name that were not buffered because the signal
+// channel already held SignalChannelOptions.MaxBufferedSignals signals.
+func GetDroppedSignalCount(ctx Context, signalName string) int {
+	return internal.GetDroppedSignalCount(ctx, signalName)
+}
+
+// SortedSignalKeys returns the keys of a map of collected signal payloads in ascending order. Go randomizes map
+// iteration order, so ranging over such a map directly from workflow code is not deterministic and breaks replay.
+// Use it to process accumulated signals in a stable order:
+//  for _, key := range workflow.SortedSignalKeys(collected) {
+//    process(collected[key])
+//  }
+func SortedSignalKeys(collected map[string][]byte) []string {
+	return internal.SortedSignalKeys(collected)
+}
+
+// SortedMapKeys returns the keys of m, a map with string keys, in ascending order. Go randomizes map iteration order,
+// so ranging over a map directly from workflow code is not deterministic and breaks replay. Use it to iterate over
+// any map in a stable order:
+//  keys, err := workflow.SortedMapKeys(pending)
+//  if err != nil {
+//    return err
+//  }
+//  for _, key := range keys {
+//    process(pending[key])
+//  }
+// It returns an error if m is not a map or if its key type is not a string kind.
+func SortedMapKeys(m interface{}) ([]string, error) {
+	return internal.SortedMapKeys(m)
+}
+
+// Partition splits items into the given number of contiguous groups, preserving the order of the items. The sizes of
+// the groups differ by at most one, the first groups being the larger ones, and some groups are empty when there are
+// fewer items than parts. The result only depends on the input, so it is deterministic. Partition panics if parts is
+// not positive.
+func Partition(items []interface{}, parts int) [][]interface{} {
+	return internal.Partition(items, parts)
+}
+
+// SelectFutureOrSignal blocks until either the future becomes ready or a signal with the given name arrives, whichever
+// happens first. If the future is ready, futureReady is true and fv, ferr hold the value and error the future was set
+// with. Encoded results, like the ones of ExecuteActivity, are returned as []byte, call f.Get to decode them as it
+// no longer blocks. No signal is consumed in this case, so signals stay buffered on the channel returned by
+// GetSignalChannel for later processing. Otherwise futureReady is false and signalValue holds the received signal as
+// an encoded.Value to decode the payload from.
+//
+// Like Selector, it is deterministic: when both the future and a signal are already ready, the future wins.
+//  ready, _, _, sig := workflow.SelectFutureOrSignal(ctx, activityFuture, "cancel")
+//  if !ready {
+//    var reason string
+//    sig.(encoded.Value).Get(&reason)
+//  }
+func SelectFutureOrSignal(ctx Context, f Future, signalName string) (futureReady bool, fv interface{}, ferr error, signalValue interface{}) {
+	return internal.SelectFutureOrSignal(ctx, f, signalName)
+}
+
 // SideEffect executes the provided function once, records its result into the workflow history. The recorded result on
 // history will be returned without executing the provided function during replay. This guarantees the deterministic
 // requirement for workflow as the exact same result will be returned in replay.
@@ -340,6 +535,17 @@ func GetVersion(ctx Context, changeID string, minSupported, maxSupported Version
 	return internal.GetVersion(ctx, changeID, minSupported, maxSupported)
 }
 
+// GetVersionHistory returns the versions of all the changeIDs recorded so far in this run, keyed by changeID. A version
+// is recorded by the first GetVersion call for its changeID, or found in the history on replay. Use it to audit which
+// code paths an execution took:
+//  for changeID, version := range workflow.GetVersionHistory(ctx) {
+//    logger.Info("Change version.", zap.String("ChangeID", changeID), zap.Int("Version", int(version)))
+//  }
+// The returned map is a copy, so changing it has no effect on the workflow.
+func GetVersionHistory(ctx Context) map[string]Version {
+	return internal.GetVersionHistory(ctx)
+}
+
 // SetQueryHandler sets the query handler to handle workflow query. The queryType specify which query type this handler
 // should handle. The handler must be a function that returns 2 values. The first return value must be a serializable
 // result. The second return value must be an error. The handler function could receive any number of input parameters.
@@ -399,6 +605,23 @@ func IsReplaying(ctx Context) bool {
 	return internal.IsReplaying(ctx)
 }
 
+// IsFirstDecision returns true while the workflow code runs as part of the first decision task of the workflow run,
+// that is before any prior decision task of the run completed. Unlike IsReplaying, the value is derived from the
+// workflow history and is the same on replay, so it is safe to use it to make decisions, for example to run one-time
+// initialization.
+func IsFirstDecision(ctx Context) bool {
+	return internal.IsFirstDecision(ctx)
+}
+
+// GetStackTrace returns the stack traces of all the coroutines of the workflow, the calling one included, in the
+// format of a goroutine dump. Each stack trace starts with the name of the coroutine, as given to GoNamed, and what it
+// is blocked on, for example "coroutine poller [blocked on updates.Receive]:". Use it to diagnose a workflow that does
+// not make progress:
+//  workflow.GetLogger(ctx).Debug("Waiting for updates", zap.String("StackTrace", workflow.GetStackTrace(ctx)))
+func GetStackTrace(ctx Context) string {
+	return internal.GetStackTrace(ctx)
+}
+
 // HasLastCompletionResult checks if there is completion result from previous runs.
 // This is used in combination with cron schedule. A workflow can be started with an optional cron schedule.
 // If a cron workflow wants to pass some data to next schedule, it can return any data and that data will become